@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nergie/no-barrel-file/internal/baseline"
+	"github.com/nergie/no-barrel-file/internal/cmd_flag"
+	"github.com/nergie/no-barrel-file/internal/gitdiff"
+	"github.com/nergie/no-barrel-file/internal/tokenizer"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// checkViolationsExitCode is the exit code of check when barrel imports are found
+	checkViolationsExitCode = 1
+	// checkErrorExitCode is the exit code of check when it can't run, so that CI
+	// can tell a failure of the tool from barrel imports
+	checkErrorExitCode = 2
+)
+
+type CheckConfig struct {
+	BarrelImportsConfig
+	baselinePath      string
+	writeBaselinePath string
+	diffBase          string
+}
+
+func NewCheckConfig(cmd *cobra.Command) CheckConfig {
+	return CheckConfig{
+		BarrelImportsConfig: NewBarrelImportsConfig(cmd),
+		baselinePath:        cmd_flag.Baseline(cmd),
+		writeBaselinePath:   cmd_flag.WriteBaseline(cmd),
+		diffBase:            cmd_flag.DiffBase(cmd),
+	}
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Fail when files of the target path import barrel files",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Usage is only relevant to flag errors, and errors are printed by Execute
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		config := NewCheckConfig(cmd)
+		barrelImports, err := checkBarrelImports(cmd, config)
+		if err != nil {
+			return err
+		}
+		if config.writeBaselinePath != "" {
+			entries := []baseline.Entry{}
+			for _, barrelImport := range barrelImports {
+				entries = append(entries, getBaselineEntries(config.rootPath, barrelImport)...)
+			}
+			if err := baseline.New(entries).Write(filepath.Join(config.rootPath, config.writeBaselinePath)); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%d barrel imports written to %s\n", len(barrelImports), config.writeBaselinePath)
+			return nil
+		}
+
+		staleEntries := []baseline.Entry{}
+		if config.baselinePath != "" {
+			tolerated, err := baseline.Read(filepath.Join(config.rootPath, config.baselinePath))
+			if err != nil {
+				return err
+			}
+			barrelImports, staleEntries = filterBaselineImports(config.rootPath, barrelImports, tolerated)
+		}
+		if config.diffBase != "" {
+			addedLines, err := gitdiff.Added(config.rootPath, config.diffBase)
+			if err != nil {
+				return err
+			}
+			barrelImports = filterAddedImports(config.rootPath, barrelImports, addedLines)
+		}
+
+		for _, barrelImport := range barrelImports {
+			statement := formatStatementLine(barrelImport.statement, tokenizer.SupportsJSX(barrelImport.path))
+			fmt.Fprintf(cmd.OutOrStdout(), "%s:%d: %s\n", getRelativeSlashPath(config.rootPath, barrelImport.path), barrelImport.start.Line, statement)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%d barrel imports found\n", len(barrelImports))
+		if len(staleEntries) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%d baseline entries no longer found, run --write-baseline to prune them\n", len(staleEntries))
+			for _, entry := range staleEntries {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s from %s\n", entry.File, entry.Symbol, entry.Barrel)
+			}
+		}
+		if len(barrelImports) > 0 {
+			return exitError{code: checkViolationsExitCode}
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmd_flag.AddAliasConfigPath(checkCmd)
+	cmd_flag.AddNearestAliasConfig(checkCmd)
+	cmd_flag.AddImportMapPath(checkCmd)
+	cmd_flag.AddTargetPath(checkCmd)
+	cmd_flag.AddBarrelPath(checkCmd)
+	cmd_flag.AddDynamicImports(checkCmd)
+	cmd_flag.AddBaseline(checkCmd)
+	cmd_flag.AddWriteBaseline(checkCmd)
+	cmd_flag.AddDiffBase(checkCmd)
+	checkCmd.MarkFlagsMutuallyExclusive("baseline", "write-baseline")
+	checkCmd.MarkFlagsMutuallyExclusive("diff-base", "write-baseline")
+}
+
+// checkBarrelImports returns the barrel imports replace would rewrite, in the
+// order of the files and of the statements, without writing anything.
+func checkBarrelImports(cmd *cobra.Command, config CheckConfig) ([]barrelImport, error) {
+	barrelImports := []barrelImport{}
+	loadErrors, err := walkBarrelImports(cmd, config.BarrelImportsConfig, func(path string, info os.FileInfo, contents []byte, fileBarrelImports []barrelImport) {
+		barrelImports = append(barrelImports, fileBarrelImports...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to check the target path: %w", err)
+	}
+	// Barrel imports through the aliases of an ignored config would go unnoticed
+	if len(loadErrors) > 0 {
+		return nil, errors.Join(loadErrors...)
+	}
+	return barrelImports, nil
+}
+
+// formatStatementLine returns a statement on one line, without its comments
+// which may be line comments, its tokens being separated by a space where the
+// statement has whitespaces or comments.
+func formatStatementLine(statement string, jsx bool) string {
+	tokens := tokenizer.Tokenize(statement, jsx)
+	var line strings.Builder
+	for i, token := range tokens {
+		if i > 0 && token.Start.Offset > tokens[i-1].End.Offset {
+			line.WriteString(" ")
+		}
+		line.WriteString(statement[token.Start.Offset:token.End.Offset])
+	}
+	return line.String()
+}
+
+// getBaselineEntries returns the baseline entries of the symbols a barrel import
+// imports from the barrel.
+func getBaselineEntries(rootPath string, barrelImport barrelImport) []baseline.Entry {
+	entries := []baseline.Entry{}
+	for _, symbol := range barrelImport.symbols {
+		entries = append(entries, baseline.Entry{
+			File:   getRelativeSlashPath(rootPath, barrelImport.path),
+			Barrel: getRelativeSlashPath(rootPath, barrelImport.barrelPath),
+			Symbol: symbol,
+		})
+	}
+	return entries
+}
+
+// filterBaselineImports returns the barrel imports of symbols missing from the
+// baseline, along with the entries of the baseline no longer found.
+func filterBaselineImports(rootPath string, barrelImports []barrelImport, tolerated baseline.Baseline) ([]barrelImport, []baseline.Entry) {
+	newBarrelImports := []barrelImport{}
+	foundEntries := []baseline.Entry{}
+	for _, barrelImport := range barrelImports {
+		entries := getBaselineEntries(rootPath, barrelImport)
+		for _, entry := range entries {
+			if !tolerated.Contains(entry) {
+				newBarrelImports = append(newBarrelImports, barrelImport)
+				break
+			}
+		}
+		foundEntries = append(foundEntries, entries...)
+	}
+	return newBarrelImports, tolerated.StaleEntries(foundEntries)
+}
+
+// filterAddedImports returns the barrel imports with a line added since the
+// diff base, such as a specifier added to an existing multiline import.
+func filterAddedImports(rootPath string, barrelImports []barrelImport, addedLines gitdiff.AddedLines) []barrelImport {
+	addedImports := []barrelImport{}
+	for _, barrelImport := range barrelImports {
+		endLine := barrelImport.start.Line + strings.Count(barrelImport.statement, "\n")
+		if addedLines.Overlaps(getRelativeSlashPath(rootPath, barrelImport.path), barrelImport.start.Line, endLine) {
+			addedImports = append(addedImports, barrelImport)
+		}
+	}
+	return addedImports
+}
+
+// getRelativeSlashPath returns path relative to the root path with forward
+// slashes, so that it is the same on every platform.
+func getRelativeSlashPath(rootPath string, path string) string {
+	relativePath, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relativePath)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nergie/no-barrel-file/internal/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCommand(t *testing.T) {
+	output, err := tests.ExecuteCommand(rootCmd, "check", "--root-path", "../tests/data/input", "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json")
+	assert.Equal(t, exitError{code: checkViolationsExitCode}, err)
+	assert.Contains(t, output, "barrel-circular/circular-a.ts:1: import { CircularB } from \".\";\n")
+	assert.Contains(t, output, "re-export-barrel-in-use.ts:3: export { PrimaryButton as MainButton, type ButtonProps } from './barrel-nested'\n")
+	assert.Contains(t, output, "27 barrel imports found\n")
+
+	output, err = tests.ExecuteCommand(rootCmd, "check", "--root-path", "../tests/data/expected", "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "0 barrel imports found\n")
+
+	_, err = tests.ExecuteCommand(rootCmd, "check", "--root-path", "../tests/data/input", "--target-path", "missing")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, exitError{code: checkViolationsExitCode})
+}
+
+func TestCheckCommandBaseline(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDirPath := "../tests/data/input"
+	rootPath := filepath.Join(tmpDir, inputDirPath)
+	defer os.RemoveAll(tmpDir)
+	tests.CopyDir(inputDirPath, rootPath)
+
+	output, err := tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json", "--write-baseline", ".no-barrel-baseline.json")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "27 barrel imports written to .no-barrel-baseline.json\n")
+	baselineContents, _ := os.ReadFile(filepath.Join(rootPath, ".no-barrel-baseline.json"))
+	assert.Contains(t, string(baselineContents), `{
+      "file": "ambiguous-barrel-in-use.ts",
+      "barrel": "barrel-basic",
+      "symbol": "BASIC_LET_SINGLE_EXPORT"
+    }`)
+
+	output, err = tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json", "--baseline", ".no-barrel-baseline.json")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "0 barrel imports found\n")
+
+	// Line shifts don't matter, new symbols and removed ones do
+	os.WriteFile(filepath.Join(rootPath, "ambiguous-barrel-in-use.ts"), []byte("\nimport { BASIC_VAR } from \"./barrel-basic\";\nimport { Button } from \"./barrel-nested\";\n"), 0644)
+	output, err = tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json", "--baseline", ".no-barrel-baseline.json")
+	assert.Equal(t, exitError{code: checkViolationsExitCode}, err)
+	assert.Contains(t, output, "ambiguous-barrel-in-use.ts:2: import { BASIC_VAR } from \"./barrel-basic\";\n1 barrel imports found\n")
+	assert.Contains(t, output, "1 baseline entries no longer found, run --write-baseline to prune them\nambiguous-barrel-in-use.ts: BASIC_LET_SINGLE_EXPORT from barrel-basic\n")
+
+	_, err = tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--baseline", "missing.json")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, exitError{code: checkViolationsExitCode})
+}
+
+func TestCheckCommandDiffBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tmpDir := t.TempDir()
+	inputDirPath := "../tests/data/input"
+	rootPath := filepath.Join(tmpDir, inputDirPath)
+	defer os.RemoveAll(tmpDir)
+	tests.CopyDir(inputDirPath, rootPath)
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "--all"}, {"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--message", "initial"}} {
+		git := exec.Command("git", args...)
+		git.Dir = rootPath
+		assert.NoError(t, git.Run())
+	}
+	contents, _ := os.ReadFile(filepath.Join(rootPath, "ambiguous-barrel-in-use.ts"))
+	os.WriteFile(filepath.Join(rootPath, "ambiguous-barrel-in-use.ts"), append(contents, "import { BASIC_VAR } from \"./barrel-basic\";\n"...), 0644)
+	os.WriteFile(filepath.Join(rootPath, "untracked-barrel-in-use.ts"), []byte("import { Button } from \"./barrel-nested\";\n"), 0644)
+
+	output, err := tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json", "--diff-base", "HEAD")
+
+	assert.Equal(t, exitError{code: checkViolationsExitCode}, err)
+	assert.Contains(t, output, "ambiguous-barrel-in-use.ts:3: import { BASIC_VAR } from \"./barrel-basic\";\nuntracked-barrel-in-use.ts:1: import { Button } from \"./barrel-nested\";\n2 barrel imports found\n")
+
+	_, err = tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--diff-base", "missing-revision")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, exitError{code: checkViolationsExitCode})
+}
+
+func TestCheckCommandMalformedConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDirPath := "../tests/data/input-root-dirs"
+	rootPath := filepath.Join(tmpDir, inputDirPath)
+	defer os.RemoveAll(tmpDir)
+	tests.CopyDir(inputDirPath, rootPath)
+	os.WriteFile(filepath.Join(rootPath, "tsconfig.json"), []byte(`{ "compilerOptions": { "rootDirs": ["src", "generated"] `), 0644)
+
+	_, err := tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--alias-config-path", "tsconfig.json")
+	assert.ErrorContains(t, err, "unable to load the tsconfig "+filepath.Join(rootPath, "tsconfig.json"))
+	assert.NotErrorIs(t, err, exitError{code: checkViolationsExitCode})
+
+	_, err = tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--nearest-alias-config")
+	assert.ErrorContains(t, err, "unable to load the tsconfig "+filepath.Join(rootPath, "tsconfig.json"))
+
+	os.WriteFile(filepath.Join(rootPath, "pnpm-workspace.yaml"), []byte("packages: [packages/*\n"), 0644)
+	_, err = tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath)
+	assert.ErrorContains(t, err, "unable to load the workspaces "+filepath.Join(rootPath, "pnpm-workspace.yaml"))
+}
+
+func TestCheckCommandMultilineStatement(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDirPath := "../tests/data/input-root-dirs"
+	rootPath := filepath.Join(tmpDir, inputDirPath)
+	defer os.RemoveAll(tmpDir)
+	tests.CopyDir(inputDirPath, rootPath)
+	os.WriteFile(filepath.Join(rootPath, "src", "page.ts"), []byte(`import {
+  getUser, // users
+  /* orders */ getOrder,
+} from "./services";
+
+export const Page = () => [getUser(), getOrder()];
+`), 0644)
+
+	output, err := tests.ExecuteCommand(rootCmd, "check", "--root-path", rootPath, "--alias-config-path", "tsconfig.json", "--target-path", "src/page.ts")
+
+	assert.Equal(t, exitError{code: checkViolationsExitCode}, err)
+	assert.Contains(t, output, "src/page.ts:1: import { getUser, getOrder, } from \"./services\";\n1 barrel imports found\n")
+}
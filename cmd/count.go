@@ -1,24 +1,47 @@
 package cmd
 
 import (
+	"github.com/nergie/no-barrel-file/internal/cmd_flag"
 	"github.com/nergie/no-barrel-file/internal/ignorer"
 	"github.com/nergie/no-barrel-file/internal/parser"
+	"github.com/nergie/no-barrel-file/internal/resolver"
 
 	"github.com/spf13/cobra"
 )
 
+type CountConfig struct {
+	RootConfig
+	aliasConfigPath    string
+	nearestAliasConfig bool
+}
+
+func NewCountConfig(cmd *cobra.Command) CountConfig {
+	return CountConfig{
+		RootConfig:         NewRootConfig(cmd),
+		aliasConfigPath:    cmd_flag.AliasConfigPath(cmd),
+		nearestAliasConfig: cmd_flag.NearestAliasConfig(cmd),
+	}
+}
+
 var countCmd = &cobra.Command{
 	Use:   "count",
 	Short: "Count barrel files in the root path",
 	Run: func(cmd *cobra.Command, args []string) {
-		config := NewRootConfig(cmd)
+		config := NewCountConfig(cmd)
 		countBarrelFiles(cmd, config)
 	},
 }
 
-func countBarrelFiles(cmd *cobra.Command, config RootConfig) {
+func init() {
+	cmd_flag.AddAliasConfigPath(countCmd)
+	cmd_flag.AddNearestAliasConfig(countCmd)
+}
+
+func countBarrelFiles(cmd *cobra.Command, config CountConfig) {
 	ignorer := ignorer.New(config.rootPath, config.ignorePaths, config.gitIgnorePath)
-	parser := parser.New(config.rootPath, ignorer, config.extensions)
+	// Barrels may re-export the modules of the other rootDirs of their config
+	resolver := resolver.New(config.rootPath, &config.aliasConfigPath, nil, config.nearestAliasConfig)
+	parser := parser.New(config.rootPath, ignorer, config.extensions, resolver.RootDirs)
 	barrelFiles := parser.BarrelFilePaths()
 	cmd.Println(len(barrelFiles))
 }
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/nergie/no-barrel-file/internal/tests"
@@ -13,3 +15,26 @@ func TestCountCommand(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, output, "5\n")
 }
+
+func TestCountCommandRootDirs(t *testing.T) {
+	rootPath := t.TempDir()
+	packagePath := filepath.Join(rootPath, "packages", "app")
+	os.MkdirAll(filepath.Join(packagePath, "src", "services"), 0755)
+	os.MkdirAll(filepath.Join(packagePath, "generated", "services"), 0755)
+	os.WriteFile(filepath.Join(packagePath, "tsconfig.json"), []byte(`{ "compilerOptions": { "rootDirs": ["src", "generated"] } }`), 0644)
+	// The barrel only re-exports a module of the other rootDir
+	os.WriteFile(filepath.Join(packagePath, "src", "services", "index.ts"), []byte("export * from \"./orders\";\n"), 0644)
+	os.WriteFile(filepath.Join(packagePath, "generated", "services", "orders.ts"), []byte("export const getOrder = () => \"order\";\n"), 0644)
+
+	output, err := tests.ExecuteCommand(rootCmd, "count", "--root-path", rootPath)
+	assert.NoError(t, err)
+	assert.Contains(t, output, "0\n")
+
+	output, err = tests.ExecuteCommand(rootCmd, "count", "--root-path", rootPath, "--alias-config-path", "packages/app/tsconfig.json")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "1\n")
+
+	output, err = tests.ExecuteCommand(rootCmd, "count", "--root-path", rootPath, "--nearest-alias-config")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "1\n")
+}
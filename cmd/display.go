@@ -1,26 +1,55 @@
 package cmd
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/nergie/no-barrel-file/internal/cmd_flag"
+	"github.com/nergie/no-barrel-file/internal/data"
 	"github.com/nergie/no-barrel-file/internal/ignorer"
 	"github.com/nergie/no-barrel-file/internal/parser"
+	"github.com/nergie/no-barrel-file/internal/resolver"
+	"github.com/nergie/no-barrel-file/internal/tokenizer"
 
 	"github.com/spf13/cobra"
 )
 
+type DisplayConfig struct {
+	RootConfig
+	aliasConfigPath    string
+	nearestAliasConfig bool
+	importMapPath      string
+}
+
+func NewDisplayConfig(cmd *cobra.Command) DisplayConfig {
+	return DisplayConfig{
+		RootConfig:         NewRootConfig(cmd),
+		aliasConfigPath:    cmd_flag.AliasConfigPath(cmd),
+		nearestAliasConfig: cmd_flag.NearestAliasConfig(cmd),
+		importMapPath:      cmd_flag.ImportMapPath(cmd),
+	}
+}
+
 var displayCmd = &cobra.Command{
 	Use:   "display",
 	Short: "Display barrel files in the root path",
 	Run: func(cmd *cobra.Command, args []string) {
-		config := NewRootConfig(cmd)
+		config := NewDisplayConfig(cmd)
 		displayBarrelFiles(cmd, config)
 	},
 }
 
-func displayBarrelFiles(cmd *cobra.Command, config RootConfig) {
+func init() {
+	cmd_flag.AddAliasConfigPath(displayCmd)
+	cmd_flag.AddNearestAliasConfig(displayCmd)
+	cmd_flag.AddImportMapPath(displayCmd)
+}
+
+func displayBarrelFiles(cmd *cobra.Command, config DisplayConfig) {
 	ignorer := ignorer.New(config.rootPath, config.ignorePaths, config.gitIgnorePath)
-	parser := parser.New(config.rootPath, ignorer, config.extensions)
+	resolver := resolver.New(config.rootPath, &config.aliasConfigPath, &config.importMapPath, config.nearestAliasConfig)
+	parser := parser.New(config.rootPath, ignorer, config.extensions, resolver.RootDirs)
 	barrelPaths := parser.BarrelFilePaths()
 	cmd.Printf("%d barrel files found\n", len(barrelPaths))
 	for _, fullPath := range barrelPaths {
@@ -29,4 +58,68 @@ func displayBarrelFiles(cmd *cobra.Command, config RootConfig) {
 			cmd.Println(relativePath)
 		}
 	}
+
+	ambiguousExports := parser.AmbiguousExports()
+	if len(ambiguousExports) > 0 {
+		cmd.Printf("%d ambiguous exports found\n", len(ambiguousExports))
+		for _, ambiguousExport := range ambiguousExports {
+			relativePath, err := filepath.Rel(config.rootPath, ambiguousExport.BarrelPath)
+			if err == nil {
+				cmd.Printf("%s: %s is exported by %s\n", relativePath, ambiguousExport.Name, strings.Join(ambiguousExport.ModulePaths, ", "))
+			}
+		}
+	}
+
+	barrelResolvedPaths := data.NewBarrelResolvedPath(parser, resolver)
+	dynamicImports := getDynamicBarrelImports(config.rootPath, parser, ignorer, barrelResolvedPaths)
+	if len(dynamicImports) > 0 {
+		cmd.Printf("%d dynamic imports of barrel files found\n", len(dynamicImports))
+		for _, dynamicImport := range dynamicImports {
+			relativePath, err := filepath.Rel(config.rootPath, dynamicImport.path)
+			if err == nil {
+				statement := dynamicImport.statement
+				cmd.Printf("%s:%d: import(%s%s%s)\n", relativePath, statement.Start.Line, statement.Quote, statement.Source, statement.Quote)
+			}
+		}
+	}
+}
+
+// dynamicBarrelImport is an `import('barrel')` expression found in the file at path
+type dynamicBarrelImport struct {
+	path      string
+	statement tokenizer.DynamicImport
+}
+
+// getDynamicBarrelImports lists the dynamic imports of barrel files, which load
+// the whole barrel in lazily loaded chunks.
+func getDynamicBarrelImports(rootPath string, parser parser.Parser, ignorer ignorer.Ignorer, barrelResolvedPaths data.BarrelResolvedPath) []dynamicBarrelImport {
+	dynamicImports := []dynamicBarrelImport{}
+	filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ignorer.IgnorePath(path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !parser.IsSupportedFileExtension(path) {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		module := tokenizer.Parse(string(contents), tokenizer.SupportsJSX(path))
+		for _, statement := range module.DynamicImports {
+			resolvedPathKey, _ := getResolvedPathKey(path, statement.Source, barrelResolvedPaths.Resolver)
+			if barrelResolvedPaths.IsResolved(resolvedPathKey) {
+				dynamicImports = append(dynamicImports, dynamicBarrelImport{path: path, statement: statement})
+			}
+		}
+		return nil
+	})
+	return dynamicImports
 }
@@ -9,7 +9,9 @@ import (
 )
 
 func TestDisplayCommand(t *testing.T) {
-	output, err := tests.ExecuteCommand(rootCmd, "display", "--root-path", "../tests/data/input", "--ignore-paths", "ignored")
+	output, err := tests.ExecuteCommand(rootCmd, "display", "--root-path", "../tests/data/input", "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json")
 	assert.NoError(t, err)
 	assert.Contains(t, output, "5 barrel files found\nbarrel-basic/index.ts\nbarrel-circular/index.ts\nbarrel-nested/Buttons/index.ts\nbarrel-nested/index.ts\nbarrel-nested/nested/index.ts\n")
+	assert.Contains(t, output, "1 ambiguous exports found\nbarrel-basic/index.ts: AMBIGUOUS_CONST is exported by ambiguous-a, ambiguous-b\n")
+	assert.Contains(t, output, "3 dynamic imports of barrel files found\ndynamic-barrel-in-use.ts:2: import(\"@barrel-basic\")\ndynamic-barrel-in-use.ts:3: import('./barrel-nested')\ndynamic-barrel-in-use.ts:7: import(\"@barrel-nested\")\n")
 }
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nergie/no-barrel-file/internal/tokenizer"
+)
+
+// statementFormat is the formatting of a statement importing a barrel, which
+// the statements replacing it keep so that only specifiers and paths change.
+type statementFormat struct {
+	newline       string // line ending of the file, `\n` or `\r\n`
+	indent        string // indentation of the statement
+	multiline     bool   // whether the specifiers of the `{ ... }` clause are on their own lines
+	itemIndent    string // indentation of the specifiers of a multiline clause
+	closingIndent string // indentation of the `}` of a multiline clause
+	padding       string // spaces inside the braces of a single-line clause
+	trailingComma bool
+	items         map[string]clauseItem // specifiers of the clause, by alias
+}
+
+// clauseItem is a specifier of the original `{ ... }` clause, with its comments
+type clauseItem struct {
+	text          string // source text of the specifier, such as `Button as PrimaryButton`
+	leading       string // lines above a multiline specifier and the indentation of its line, or comments before a single-line one
+	beforeComma   string // comments between the specifier and its comma
+	afterComma    string // comments after the comma, on the line of a multiline specifier
+	trailingLines string // lines between the last multiline specifier and `}`
+}
+
+// clauseSpecifier is a specifier formatted for a replacing statement, along
+// with the alias of the original specifier it comes from.
+type clauseSpecifier struct {
+	alias string
+	text  string
+}
+
+// getStatementFormat returns the format of the statement from start to end of
+// contents. The `{ ... }` clause is the first one of the statement when
+// hasClause is set, such as the specifiers of an import or the object pattern
+// of a require.
+func getStatementFormat(contents string, tokens []tokenizer.Token, start tokenizer.Position, end tokenizer.Position, hasClause bool) statementFormat {
+	format := statementFormat{
+		newline: getNewline(contents),
+		indent:  getLineIndent(contents, start.Offset),
+		padding: " ",
+		items:   make(map[string]clauseItem),
+	}
+	if !hasClause {
+		return format
+	}
+
+	open := sort.Search(len(tokens), func(i int) bool { return tokens[i].Start.Offset >= start.Offset })
+	for open < len(tokens) && tokens[open].Start.Offset < end.Offset && !isPunctuator(tokens[open], "{") {
+		open++
+	}
+	if open >= len(tokens) || tokens[open].Start.Offset >= end.Offset {
+		return format
+	}
+
+	// Token indexes of the first and last tokens of each specifier, and of its comma if any
+	type itemTokens struct{ first, last, comma int }
+	items := []itemTokens{}
+	closing, depth, itemStart := -1, 0, -1
+	for i := open + 1; i < len(tokens) && closing < 0; i++ {
+		switch {
+		case isPunctuator(tokens[i], "{") || isPunctuator(tokens[i], "[") || isPunctuator(tokens[i], "("):
+			depth++
+		case isPunctuator(tokens[i], "}") && depth == 0:
+			closing = i
+			if itemStart >= 0 {
+				items = append(items, itemTokens{first: itemStart, last: i - 1, comma: -1})
+			}
+			continue
+		case isPunctuator(tokens[i], "}") || isPunctuator(tokens[i], "]") || isPunctuator(tokens[i], ")"):
+			depth--
+		case isPunctuator(tokens[i], ",") && depth == 0:
+			if itemStart >= 0 {
+				items = append(items, itemTokens{first: itemStart, last: i - 1, comma: i})
+			}
+			itemStart = -1
+			continue
+		}
+		if itemStart < 0 {
+			itemStart = i
+		}
+	}
+	if closing < 0 {
+		return format
+	}
+
+	format.multiline = strings.Contains(contents[tokens[open].End.Offset:tokens[closing].Start.Offset], "\n")
+	format.trailingComma = len(items) > 0 && items[len(items)-1].comma >= 0
+	if format.multiline {
+		format.closingIndent = getLineIndent(contents, tokens[closing].Start.Offset)
+		if len(items) > 0 {
+			format.itemIndent = getLineIndent(contents, tokens[items[0].first].Start.Offset)
+		}
+	} else if !strings.HasPrefix(contents[tokens[open].End.Offset:], " ") {
+		format.padding = ""
+	}
+
+	for i, itemTokens := range items {
+		first, last := tokens[itemTokens.first], tokens[itemTokens.last]
+		// The trivia before a specifier follows the `{` or the previous comma
+		leadingTrivia := contents[tokens[itemTokens.first-1].End.Offset:first.Start.Offset]
+		beforeCommaTrivia := ""
+		afterTriviaStart := last.End.Offset
+		afterTriviaEnd := tokens[itemTokens.last+1].Start.Offset
+		if itemTokens.comma >= 0 {
+			beforeCommaTrivia = contents[last.End.Offset:tokens[itemTokens.comma].Start.Offset]
+			afterTriviaStart = tokens[itemTokens.comma].End.Offset
+			afterTriviaEnd = tokens[itemTokens.comma+1].Start.Offset
+		}
+		afterTrivia := contents[afterTriviaStart:afterTriviaEnd]
+
+		item := clauseItem{text: contents[first.Start.Offset:last.End.Offset]}
+		if format.multiline {
+			// Comments on the line of the previous comma belong to the previous specifier
+			lineEnd := strings.Index(leadingTrivia, "\n")
+			if lineEnd >= 0 {
+				item.leading = leadingTrivia[lineEnd+1:]
+				if comment := strings.TrimSpace(leadingTrivia[:lineEnd]); i == 0 && comment != "" {
+					// { // comment
+					item.leading = format.itemIndent + comment + format.newline + item.leading
+				}
+			}
+			item.beforeComma = formatInlineComment(beforeCommaTrivia)
+			afterLine, remainingLines, _ := strings.Cut(afterTrivia, "\n")
+			item.afterComma = strings.TrimRight(afterLine, " \t\r")
+			if i == len(items)-1 {
+				item.trailingLines = remainingLines[:strings.LastIndex(remainingLines, "\n")+1]
+			}
+		} else {
+			if comment := strings.TrimSpace(leadingTrivia); comment != "" {
+				item.leading = comment + " "
+			}
+			item.beforeComma = formatInlineComment(beforeCommaTrivia)
+			if itemTokens.comma < 0 {
+				item.beforeComma = formatInlineComment(afterTrivia)
+			}
+		}
+		format.items[tokenizer.StringValue(last)] = item
+	}
+	return format
+}
+
+// singleLine returns the format with a single-line clause, for patterns nested
+// in another one. Comments are left out as they may be line comments.
+func (format statementFormat) singleLine() statementFormat {
+	items := make(map[string]clauseItem)
+	for alias, item := range format.items {
+		items[alias] = clauseItem{text: item.text}
+	}
+	format.items = items
+	format.multiline = false
+	format.padding = " "
+	format.trailingComma = false
+	return format
+}
+
+// join joins the statements replacing a statement, on lines of the same indentation
+func (format statementFormat) join(statements []string) string {
+	return strings.Join(statements, format.newline+format.indent)
+}
+
+// formatClause formats the `{ ... }` clause of specifiers as the original
+// clause, keeping the comments of the specifiers it still contains. The text
+// of the specifiers is kept when it only differs by whitespace.
+func (format statementFormat) formatClause(specifiers []clauseSpecifier) string {
+	var clause strings.Builder
+	clause.WriteString("{")
+	if !format.multiline {
+		clause.WriteString(format.padding)
+		for i, specifier := range specifiers {
+			item := format.items[specifier.alias]
+			if i > 0 {
+				clause.WriteString(", ")
+			}
+			clause.WriteString(item.leading + format.specifierText(specifier) + item.beforeComma)
+		}
+		if format.trailingComma {
+			clause.WriteString(",")
+		}
+		clause.WriteString(format.padding + "}")
+		return clause.String()
+	}
+
+	clause.WriteString(format.newline)
+	for i, specifier := range specifiers {
+		item, exists := format.items[specifier.alias]
+		if !exists || item.leading == "" {
+			item.leading = format.itemIndent
+		}
+		for i == 0 {
+			// Blank lines separating specifiers don't start a clause
+			line, nextLines, found := strings.Cut(item.leading, "\n")
+			if !found || strings.TrimSpace(line) != "" {
+				break
+			}
+			item.leading = nextLines
+		}
+		clause.WriteString(item.leading + format.specifierText(specifier) + item.beforeComma)
+		if i < len(specifiers)-1 || format.trailingComma {
+			clause.WriteString(",")
+		}
+		clause.WriteString(item.afterComma + format.newline + item.trailingLines)
+	}
+	clause.WriteString(format.closingIndent + "}")
+	return clause.String()
+}
+
+func (format statementFormat) specifierText(specifier clauseSpecifier) string {
+	item, exists := format.items[specifier.alias]
+	if exists && strings.Join(strings.Fields(item.text), " ") == specifier.text {
+		return item.text
+	}
+	return specifier.text
+}
+
+// formatInlineComment returns the comments of trivia on one line, preceded by a space
+func formatInlineComment(trivia string) string {
+	comment := strings.Join(strings.Fields(trivia), " ")
+	if comment == "" {
+		return ""
+	}
+	return " " + comment
+}
+
+// getNewline returns the line ending of the first line of contents
+func getNewline(contents string) string {
+	if lineEnd := strings.Index(contents, "\n"); lineEnd > 0 && contents[lineEnd-1] == '\r' {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// getLineIndent returns the whitespaces between the start of the line and
+// offset, or nothing when something else precedes offset on its line.
+func getLineIndent(contents string, offset int) string {
+	lineStart := strings.LastIndex(contents[:offset], "\n") + 1
+	indent := contents[lineStart:offset]
+	if strings.Trim(indent, " \t") != "" {
+		return ""
+	}
+	return indent
+}
+
+func isPunctuator(token tokenizer.Token, value string) bool {
+	return token.Kind == tokenizer.Punctuator && token.Value == value
+}
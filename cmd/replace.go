@@ -1,73 +1,178 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/nergie/no-barrel-file/internal/cmd_flag"
 	"github.com/nergie/no-barrel-file/internal/data"
+	"github.com/nergie/no-barrel-file/internal/diff"
 	"github.com/nergie/no-barrel-file/internal/ignorer"
 	"github.com/nergie/no-barrel-file/internal/parser"
 	"github.com/nergie/no-barrel-file/internal/resolver"
+	"github.com/nergie/no-barrel-file/internal/tokenizer"
 
 	"github.com/spf13/cobra"
 )
 
-var (
-	// import type { ModuleName } from 'module' || import { ModuleName } from 'module'
-	NamedImportLineRX = regexp.MustCompile(`import\s+(type {[^}]+}|{[^}]+})\s+from\s+(['"])([^'"]+)['"](;?)`)
-	TypeImportRX      = regexp.MustCompile(`type\s+[{]?\s*(\w+)`) // type { exportName }
-	AliasImportRX     = regexp.MustCompile(`(\w+)\s+as\s+\w+`)    // exportName as Alias
-)
+// BarrelImportsConfig configures how the barrel imports of the target path are
+// found, by the commands replacing and checking them.
+type BarrelImportsConfig struct {
+	RootConfig
+	aliasConfigPath    string
+	nearestAliasConfig bool
+	importMapPath      string
+	targetPath         string
+	barrelPath         string
+	dynamicImports     bool
+}
+
+func NewBarrelImportsConfig(cmd *cobra.Command) BarrelImportsConfig {
+	return BarrelImportsConfig{
+		RootConfig:         NewRootConfig(cmd),
+		aliasConfigPath:    cmd_flag.AliasConfigPath(cmd),
+		nearestAliasConfig: cmd_flag.NearestAliasConfig(cmd),
+		importMapPath:      cmd_flag.ImportMapPath(cmd),
+		targetPath:         cmd_flag.TargetPath(cmd),
+		barrelPath:         cmd_flag.BarrelPath(cmd),
+		dynamicImports:     cmd_flag.DynamicImports(cmd),
+	}
+}
 
 type ReplaceConfig struct {
-	RootConfig
-	aliasConfigPath string
-	targetPath      string
-	barrelPath      string
+	BarrelImportsConfig
 	verbose         bool
+	dryRun          bool
+	outputPatchPath string
 }
 
 func NewReplaceConfig(cmd *cobra.Command) ReplaceConfig {
 	return ReplaceConfig{
-		RootConfig:      NewRootConfig(cmd),
-		aliasConfigPath: cmd_flag.AliasConfigPath(cmd),
-		targetPath:      cmd_flag.TargetPath(cmd),
-		barrelPath:      cmd_flag.BarrelPath(cmd),
-		verbose:         cmd_flag.Verbose(cmd),
+		BarrelImportsConfig: NewBarrelImportsConfig(cmd),
+		verbose:             cmd_flag.Verbose(cmd),
+		dryRun:              cmd_flag.DryRun(cmd),
+		outputPatchPath:     cmd_flag.OutputPatch(cmd),
 	}
 }
 
 var replaceCmd = &cobra.Command{
 	Use:   "replace",
 	Short: "Replace barrel files imports",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Usage is only relevant to flag errors, and errors are printed by Execute
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
 		config := NewReplaceConfig(cmd)
-		updatedFilesTotal := replaceBarrelImports(cmd, config)
-		fmt.Fprintf(cmd.OutOrStdout(), "%d files updated\n", updatedFilesTotal)
+		updatedFilesTotal, patch, loadErrors, err := replaceBarrelImports(cmd, config)
+		if err != nil {
+			return err
+		}
+		// Imports through the aliases of an ignored config are left unchanged
+		loadErr := errors.Join(loadErrors...)
+		if config.outputPatchPath != "" {
+			if err := os.WriteFile(filepath.Join(config.rootPath, config.outputPatchPath), []byte(patch), 0644); err != nil {
+				return err
+			}
+		}
+		if !config.dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "%d files updated\n", updatedFilesTotal)
+			return loadErr
+		}
+		if config.outputPatchPath == "" {
+			if isColorTerminal(cmd.OutOrStdout()) {
+				patch = diff.Colorize(patch)
+			}
+			fmt.Fprint(cmd.OutOrStdout(), patch)
+		}
+		// The summary doesn't go to stdout, so that the diff can be redirected to a file
+		fmt.Fprintf(cmd.ErrOrStderr(), "%d files would be updated\n", updatedFilesTotal)
+		return loadErr
 	},
 }
 
 func init() {
 	cmd_flag.AddAliasConfigPath(replaceCmd)
+	cmd_flag.AddNearestAliasConfig(replaceCmd)
+	cmd_flag.AddImportMapPath(replaceCmd)
 	cmd_flag.AddTargetPath(replaceCmd)
 	cmd_flag.AddBarrelPath(replaceCmd)
 	cmd_flag.AddVerbose(replaceCmd)
+	cmd_flag.AddDynamicImports(replaceCmd)
+	cmd_flag.AddDryRun(replaceCmd)
+	cmd_flag.AddOutputPatch(replaceCmd)
 }
 
-func replaceBarrelImports(cmd *cobra.Command, config ReplaceConfig) int {
-	resolver := resolver.New(config.rootPath, &config.aliasConfigPath)
+// replaceBarrelImports replaces the barrel imports of the target path and
+// returns the number of updated files, along with the unified diff of their
+// changes when they are previewed or written to a patch, and the errors of the
+// alias configs which failed to load. It returns the error preventing the walk
+// of the target path.
+func replaceBarrelImports(cmd *cobra.Command, config ReplaceConfig) (int, string, []error, error) {
+	updatedFilesTotal := 0
+	var patch strings.Builder
+
+	loadErrors, err := walkBarrelImports(cmd, config.BarrelImportsConfig, func(path string, info os.FileInfo, contents []byte, barrelImports []barrelImport) {
+		edits := []textEdit{}
+		for _, barrelImport := range barrelImports {
+			if config.verbose {
+				cmd.Printf("Updating %s in %s:\nBefore:\n%s\nAfter:\n%s\n\n", barrelImport.kind, path, barrelImport.statement, barrelImport.edits[0].text)
+			}
+			edits = append(edits, barrelImport.edits...)
+		}
+		updatedContents := applyTextEdits(string(contents), edits)
+
+		if updatedContents != string(contents) {
+			if config.dryRun || config.outputPatchPath != "" {
+				if relativePath, err := filepath.Rel(config.rootPath, path); err == nil {
+					patch.WriteString(diff.Unified(filepath.ToSlash(relativePath), string(contents), updatedContents))
+				}
+			}
+			if !config.dryRun {
+				os.WriteFile(path, []byte(updatedContents), info.Mode())
+			}
+			updatedFilesTotal += 1
+		}
+	})
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("unable to replace the target path: %w", err)
+	}
+	return updatedFilesTotal, patch.String(), loadErrors, nil
+}
+
+// barrelImport is an import or a re-export of a barrel file in the file at
+// path, and the edits replacing it by imports of the modules of the barrel.
+type barrelImport struct {
+	kind       string // "imports" or "exports"
+	path       string
+	start      tokenizer.Position
+	statement  string
+	barrelPath string     // resolved path of the barrel
+	symbols    []string   // names exported by the barrel which are no longer imported from it
+	edits      []textEdit // the first one replaces the statement itself
+}
+
+// walkBarrelImports calls visit with the barrel imports of each file of the
+// target path which has any. It returns the errors of the alias configs which
+// failed to load and were ignored, and the error preventing the walk of the
+// target path.
+func walkBarrelImports(cmd *cobra.Command, config BarrelImportsConfig, visit func(path string, info os.FileInfo, contents []byte, barrelImports []barrelImport)) ([]error, error) {
+	resolver := resolver.New(config.rootPath, &config.aliasConfigPath, &config.importMapPath, config.nearestAliasConfig)
 	ignorer := ignorer.New(config.rootPath, config.ignorePaths, config.gitIgnorePath)
 	parserRootPath := joinCrossPlatformPaths(config.rootPath, config.barrelPath)
-	parser := parser.New(parserRootPath, ignorer, config.extensions)
+	parser := parser.New(parserRootPath, ignorer, config.extensions, resolver.RootDirs)
 	barrelResolvedPaths := data.NewBarrelResolvedPath(parser, resolver)
+	barrelFilePaths := make(map[string]struct{})
+	for _, barrelFilePath := range parser.BarrelFilePaths() {
+		barrelFilePaths[barrelFilePath] = struct{}{}
+	}
 	targetFullPath := joinCrossPlatformPaths(config.rootPath, config.targetPath)
-	updatedFilesTotal := 0
 
-	filepath.Walk(targetFullPath, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(targetFullPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return err
 		}
@@ -88,115 +193,572 @@ func replaceBarrelImports(cmd *cobra.Command, config ReplaceConfig) int {
 			return nil
 		}
 
-		updatedContents := NamedImportLineRX.ReplaceAllStringFunc(string(contents), func(importStatement string) string {
-			matches := NamedImportLineRX.FindStringSubmatch(importStatement)
-			if len(matches) < 4 {
-				return importStatement
+		module := tokenizer.Parse(string(contents), tokenizer.SupportsJSX(path))
+		barrelImports := []barrelImport{}
+		appendBarrelImport := func(barrelImport barrelImport, kind string, start tokenizer.Position, end tokenizer.Position) {
+			barrelImport.kind = kind
+			barrelImport.path = path
+			barrelImport.start = start
+			barrelImport.statement = string(contents[start.Offset:end.Offset])
+			barrelImports = append(barrelImports, barrelImport)
+		}
+		for _, statement := range module.Imports {
+			reportAmbiguousImports(cmd, statement.Source, statement.Specifiers, path, barrelResolvedPaths)
+			format := getStatementFormat(string(contents), module.Tokens, statement.Start, statement.End, statement.HasNamed || statement.Require != "")
+			if barrelImport, replaced := replaceImportStatement(statement, module, format, path, barrelResolvedPaths); replaced {
+				appendBarrelImport(barrelImport, "imports", statement.Start, statement.End)
+			}
+		}
+		if _, isBarrelFile := barrelFilePaths[path]; !isBarrelFile {
+			for _, statement := range module.Exports {
+				if statement.Source != "" {
+					reportAmbiguousImports(cmd, statement.Source, statement.Specifiers, path, barrelResolvedPaths)
+				}
+				format := getStatementFormat(string(contents), module.Tokens, statement.Start, statement.End, !statement.IsStar)
+				if barrelImport, replaced := replaceExportStatement(statement, format, path, barrelResolvedPaths); replaced {
+					appendBarrelImport(barrelImport, "exports", statement.Start, statement.End)
+				}
 			}
+		}
+		if config.dynamicImports {
+			for _, statement := range module.DynamicImports {
+				reportAmbiguousImports(cmd, statement.Source, statement.Specifiers, path, barrelResolvedPaths)
+				format := getStatementFormat(string(contents), module.Tokens, statement.Start, statement.End, statement.Declaration != "")
+				if barrelImport, replaced := replaceDynamicImport(statement, format, path, barrelResolvedPaths); replaced {
+					appendBarrelImport(barrelImport, "imports", statement.Start, statement.End)
+				}
+			}
+		}
+		if len(barrelImports) > 0 {
+			visit(path, info, contents, barrelImports)
+		}
+		return nil
+	})
+	return resolver.LoadErrors(), err
+}
+
+// isColorTerminal reports whether output is a terminal accepting colors,
+// which NO_COLOR disables.
+func isColorTerminal(output io.Writer) bool {
+	file, isFile := output.(*os.File)
+	if !isFile || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// textEdit replaces the contents between two byte offsets of a file
+type textEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+func applyTextEdits(contents string, edits []textEdit) string {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var updatedContents strings.Builder
+	lastOffset := 0
+	for _, edit := range edits {
+		if edit.start < lastOffset {
+			continue
+		}
+		updatedContents.WriteString(contents[lastOffset:edit.start])
+		updatedContents.WriteString(edit.text)
+		lastOffset = edit.end
+	}
+	updatedContents.WriteString(contents[lastOffset:])
+	return updatedContents.String()
+}
 
-			importNames := strings.Split(matches[1], ",")
-			quoteSymbol := matches[2]
-			importPath := matches[3]
-			endSymbol := matches[4]
-			isAliasPath := strings.HasPrefix(importPath, "@")
-			var resolvedPathKey string
-			if isAliasPath {
-				resolvedPathKey = importPath
+// replaceImportStatement returns the barrel import with the edits replacing
+// it, the first one being the replacement of the import statement itself.
+func replaceImportStatement(statement tokenizer.Import, module tokenizer.Module, format statementFormat, path string, barrelResolvedPaths data.BarrelResolvedPath) (barrelImport, bool) {
+	resolvedPathKey, isAliasPath := getResolvedPathKey(path, statement.Source, barrelResolvedPaths.Resolver)
+	if !barrelResolvedPaths.IsResolved(resolvedPathKey) {
+		return barrelImport{}, false
+	}
+
+	specifiers := statement.Specifiers
+	var memberEdits []textEdit
+	if statement.Namespace != "" && statement.Require != "" {
+		// const lib = require('../lib') is left unchanged, as its properties may be reassigned
+		return barrelImport{}, false
+	}
+	if statement.Namespace != "" {
+		// import * as UI from '@ui' is handled as import { Button, Modal } from '@ui' for each UI.Button and UI.Modal used
+		var isReplaceable bool
+		specifiers, memberEdits, isReplaceable = getNamespaceMembers(statement, module, resolvedPathKey, barrelResolvedPaths)
+		if !isReplaceable {
+			return barrelImport{}, false
+		}
+	} else if !statement.HasNamed && statement.Default == "" {
+		return barrelImport{}, false
+	}
+	if statement.Default != "" {
+		// import Default, { a } from 'module' is handled as import { default as Default, a } from 'module'
+		specifiers = append([]tokenizer.Specifier{{Name: "default", Alias: statement.Default}}, specifiers...)
+	}
+
+	replacedImports := []string{}
+	orderedImportPaths, importsByModule, replacedNames := groupSpecifiersByModule(path, statement.Source, specifiers, resolvedPathKey, isAliasPath, barrelResolvedPaths)
+	if len(replacedNames) == 0 {
+		return barrelImport{}, false
+	}
+
+	endSymbol := ""
+	if statement.Semicolon {
+		endSymbol = ";"
+	}
+	for _, resolvedPath := range orderedImportPaths {
+		specifiers := importsByModule[resolvedPath]
+		if statement.Require != "" {
+			replacedImports = append(replacedImports, formatRequireStatements(statement, format, resolvedPath, specifiers)...)
+			continue
+		}
+		fromClause := fmt.Sprintf(" from %s%s%s%s", statement.Quote, resolvedPath, statement.Quote, endSymbol)
+		namedSpecifiers := []tokenizer.Specifier{}
+		namespaceImports := []string{}
+		for _, specifier := range specifiers {
+			if specifier.Name == "*" {
+				// A namespace import can't be combined with named imports
+				namespaceImport := "import "
+				if statement.IsType || specifier.IsType {
+					namespaceImport += "type "
+				}
+				namespaceImports = append(namespaceImports, namespaceImport+"* as "+specifier.Alias+fromClause)
 			} else {
-				resolvedPathKey = joinCrossPlatformPaths(filepath.Dir(path), importPath)
+				namedSpecifiers = append(namedSpecifiers, specifier)
 			}
+		}
 
-			if !barrelResolvedPaths.IsResolved(resolvedPathKey) {
-				return importStatement
-			}
+		if len(namedSpecifiers) > 0 {
+			isTypeImport := statement.IsType || (len(namedSpecifiers) == 1 && namedSpecifiers[0].IsType)
+			replacedImports = append(replacedImports, formatImportStatement(format, isTypeImport, namedSpecifiers)+fromClause)
+		}
+		replacedImports = append(replacedImports, namespaceImports...)
+	}
 
-			replacedImports := []string{}
-			importsByModule := make(map[string][]string)
-			orderedImportPaths := []string{}
+	if len(replacedImports) == 0 {
+		return barrelImport{}, false
+	}
+	statementEdit := textEdit{
+		start: statement.Start.Offset,
+		end:   statement.End.Offset,
+		text:  format.join(replacedImports),
+	}
+	return barrelImport{barrelPath: resolvedPathKey, symbols: replacedNames, edits: append([]textEdit{statementEdit}, memberEdits...)}, true
+}
 
-			for _, importName := range importNames {
-				importName = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(importName, "{"), "}"))
-				if importName == "" {
-					continue
-				}
-				moduleName := getModuleName(importName)
-				resolvedModulePath, exists := barrelResolvedPaths.ResolveModuleName(resolvedPathKey, moduleName)
-				var newImportPath string
-				if exists {
-					newImportPath = joinCrossPlatformPaths(resolvedPathKey, resolvedModulePath)
-					if !isAliasPath {
-						newImportPath = joinCrossPlatformPaths(importPath, resolvedModulePath)
-						if !strings.HasPrefix(newImportPath, "./") && !strings.HasPrefix(newImportPath, "../") {
-							newImportPath = "./" + newImportPath
-						}
-					}
-				} else {
-					newImportPath = importPath
-				}
-				if _, exists := importsByModule[newImportPath]; !exists {
-					orderedImportPaths = append(orderedImportPaths, newImportPath)
-				}
+// replaceExportStatement returns the barrel import with the edit replacing `export { a, b } from '@barrel'`
+// in files other than barrel files by the re-exports of the modules declaring
+// a and b.
+func replaceExportStatement(statement tokenizer.Export, format statementFormat, path string, barrelResolvedPaths data.BarrelResolvedPath) (barrelImport, bool) {
+	if statement.Source == "" || statement.IsStar {
+		return barrelImport{}, false
+	}
+	resolvedPathKey, isAliasPath := getResolvedPathKey(path, statement.Source, barrelResolvedPaths.Resolver)
+	if !barrelResolvedPaths.IsResolved(resolvedPathKey) {
+		return barrelImport{}, false
+	}
 
-				importsByModule[newImportPath] = append(importsByModule[newImportPath], importName)
+	endSymbol := ""
+	if statement.Semicolon {
+		endSymbol = ";"
+	}
+	replacedExports := []string{}
+	orderedExportPaths, exportsByModule, replacedNames := groupSpecifiersByModule(path, statement.Source, statement.Specifiers, resolvedPathKey, isAliasPath, barrelResolvedPaths)
+	if len(replacedNames) == 0 {
+		return barrelImport{}, false
+	}
+	for _, resolvedPath := range orderedExportPaths {
+		fromClause := fmt.Sprintf(" from %s%s%s%s", statement.Quote, resolvedPath, statement.Quote, endSymbol)
+		namedSpecifiers := []tokenizer.Specifier{}
+		namespaceExports := []string{}
+		for _, specifier := range exportsByModule[resolvedPath] {
+			if specifier.Name == "*" {
+				// export { UI } from '@barrel' with export * as UI from './ui'
+				namespaceExport := "export "
+				if statement.IsType || specifier.IsType {
+					namespaceExport += "type "
+				}
+				namespaceExports = append(namespaceExports, namespaceExport+"* as "+specifier.Alias+fromClause)
+			} else {
+				namedSpecifiers = append(namedSpecifiers, specifier)
 			}
+		}
 
-			for _, resolvedPath := range orderedImportPaths {
-				importNames := importsByModule[resolvedPath]
-				newImportStatement := "import "
-				isTypeImport := strings.HasPrefix(matches[1], "type {") || (len(importNames) == 1 && strings.Contains(importNames[0], "type "))
-				if isTypeImport {
-					newImportStatement += "type { "
-				} else {
-					newImportStatement += "{ "
-				}
+		if len(namedSpecifiers) > 0 {
+			replacedExports = append(replacedExports, formatExportStatement(format, statement.IsType, namedSpecifiers)+fromClause)
+		}
+		replacedExports = append(replacedExports, namespaceExports...)
+	}
 
-				for _, importName := range importNames {
-					if isTypeImport {
-						class := getModuleName(importName)
-						newImportStatement += class + ", "
-					} else {
-						newImportStatement += importName + ", "
-					}
-				}
+	if len(replacedExports) == 0 {
+		return barrelImport{}, false
+	}
+	statementEdit := textEdit{start: statement.Start.Offset, end: statement.End.Offset, text: format.join(replacedExports)}
+	return barrelImport{barrelPath: resolvedPathKey, symbols: replacedNames, edits: []textEdit{statementEdit}}, true
+}
 
-				newImportStatement = strings.TrimSuffix(newImportStatement, ", ")
-				newImportStatement += fmt.Sprintf(" } from %s%s%s%s", quoteSymbol, resolvedPath, quoteSymbol, endSymbol)
-				replacedImports = append(replacedImports, newImportStatement)
+// groupSpecifiersByModule groups the specifiers imported from a barrel by the
+// import path of the module declaring them, in order of first appearance,
+// along with the names of the specifiers no longer imported from the barrel.
+func groupSpecifiersByModule(path string, importPath string, specifiers []tokenizer.Specifier, resolvedPathKey string, isAliasPath bool, barrelResolvedPaths data.BarrelResolvedPath) ([]string, map[string][]tokenizer.Specifier, []string) {
+	importsByModule := make(map[string][]tokenizer.Specifier)
+	orderedImportPaths := []string{}
+	replacedNames := []string{}
+	for _, specifier := range specifiers {
+		moduleExport, exists := barrelResolvedPaths.ResolveModuleName(resolvedPathKey, specifier.Name)
+		newImportPath := importPath
+		if exists {
+			if resolvedImportPath, isImportable := getResolvedImportPath(path, importPath, resolvedPathKey, isAliasPath, moduleExport, barrelResolvedPaths.Resolver); isImportable {
+				// import { PrimaryButton as PB } from '@ui' with export { Button as PrimaryButton } from './button'
+				replacedNames = append(replacedNames, specifier.Name)
+				specifier.Name = moduleExport.Name
+				newImportPath = resolvedImportPath
 			}
+		}
+		if _, exists := importsByModule[newImportPath]; !exists {
+			orderedImportPaths = append(orderedImportPaths, newImportPath)
+		}
+		importsByModule[newImportPath] = append(importsByModule[newImportPath], specifier)
+	}
+	return orderedImportPaths, importsByModule, replacedNames
+}
 
-			if len(replacedImports) > 0 {
-				replacedImportStatement := strings.Join(replacedImports, "\n")
-				if config.verbose {
-					cmd.Printf("Updating imports in %s:\nBefore:\n%s\nAfter:\n%s\n\n", path, importStatement, replacedImportStatement)
-				}
-				return replacedImportStatement
+// replaceDynamicImport returns the barrel import with the edit replacing `const { a, b } = await import('@barrel')`
+// by the dynamic imports of the modules declaring a and b, loaded in parallel
+// with Promise.all when there are several.
+func replaceDynamicImport(statement tokenizer.DynamicImport, format statementFormat, path string, barrelResolvedPaths data.BarrelResolvedPath) (barrelImport, bool) {
+	resolvedPathKey, isAliasPath := getResolvedPathKey(path, statement.Source, barrelResolvedPaths.Resolver)
+	if statement.Declaration == "" || !barrelResolvedPaths.IsResolved(resolvedPathKey) {
+		return barrelImport{}, false
+	}
+
+	orderedImportPaths, importsByModule, replacedNames := groupSpecifiersByModule(path, statement.Source, statement.Specifiers, resolvedPathKey, isAliasPath, barrelResolvedPaths)
+	if len(replacedNames) == 0 {
+		return barrelImport{}, false
+	}
+	patterns := []string{}
+	dynamicImports := []string{}
+	if len(orderedImportPaths) > 1 {
+		// Patterns destructuring the modules loaded with Promise.all are nested in an array pattern
+		format = format.singleLine()
+	}
+	for _, resolvedPath := range orderedImportPaths {
+		dynamicImport := fmt.Sprintf("import(%s%s%s)", statement.Quote, resolvedPath, statement.Quote)
+		properties := []clauseSpecifier{}
+		namespaces := []string{}
+		for _, specifier := range importsByModule[resolvedPath] {
+			switch {
+			case specifier.Name == "*":
+				// The namespace is the module itself
+				namespaces = append(namespaces, specifier.Alias)
+			case specifier.Alias != specifier.Name:
+				properties = append(properties, clauseSpecifier{alias: specifier.Alias, text: specifier.Name + ": " + specifier.Alias})
+			default:
+				properties = append(properties, clauseSpecifier{alias: specifier.Alias, text: specifier.Name})
 			}
+		}
+		if len(properties) > 0 {
+			patterns = append(patterns, format.formatClause(properties))
+			dynamicImports = append(dynamicImports, dynamicImport)
+		}
+		for _, namespace := range namespaces {
+			patterns = append(patterns, namespace)
+			dynamicImports = append(dynamicImports, dynamicImport)
+		}
+	}
+	if len(patterns) == 0 {
+		return barrelImport{}, false
+	}
 
-			return importStatement
-		})
+	replacedImport := fmt.Sprintf("%s %s = await %s", statement.Declaration, patterns[0], dynamicImports[0])
+	if len(patterns) > 1 {
+		replacedImport = fmt.Sprintf("%s [%s] = await Promise.all([%s])", statement.Declaration, strings.Join(patterns, ", "), strings.Join(dynamicImports, ", "))
+	}
+	if statement.Semicolon {
+		replacedImport += ";"
+	}
+	statementEdit := textEdit{start: statement.Start.Offset, end: statement.End.Offset, text: replacedImport}
+	return barrelImport{barrelPath: resolvedPathKey, symbols: replacedNames, edits: []textEdit{statementEdit}}, true
+}
 
-		if updatedContents != string(contents) {
-			os.WriteFile(path, []byte(updatedContents), info.Mode())
-			updatedFilesTotal += 1
+// reportAmbiguousImports warns about the specifiers of an import, a re-export
+// or a destructured dynamic import of source naming what a barrel exports
+// ambiguously, which are left unchanged.
+func reportAmbiguousImports(cmd *cobra.Command, source string, specifiers []tokenizer.Specifier, path string, barrelResolvedPaths data.BarrelResolvedPath) {
+	resolvedPathKey, _ := getResolvedPathKey(path, source, barrelResolvedPaths.Resolver)
+	for _, specifier := range specifiers {
+		if modulePaths, isAmbiguous := barrelResolvedPaths.AmbiguousModulePaths(resolvedPathKey, specifier.Name); isAmbiguous {
+			cmd.PrintErrf("Ambiguous import of %s from %s in %s, exported by %s\n", specifier.Name, source, path, strings.Join(modulePaths, ", "))
 		}
+	}
+}
 
-		return nil
-	})
-	return updatedFilesTotal
+// getNamespaceMembers lists the members used through a namespace import, along
+// with the edits replacing `UI.Button` by `Button`. A namespace import is only
+// replaceable when it is exclusively used to access members exported by the
+// barrel, and when these members don't collide with other identifiers.
+func getNamespaceMembers(statement tokenizer.Import, module tokenizer.Module, resolvedPathKey string, barrelResolvedPaths data.BarrelResolvedPath) ([]tokenizer.Specifier, []textEdit, bool) {
+	specifiers := []tokenizer.Specifier{}
+	memberEdits := []textEdit{}
+	members := make(map[string]struct{})
+	identifiers := make(map[string]struct{})
+	tokens := module.Tokens
+	for i, token := range tokens {
+		if token.Start.Offset >= statement.Start.Offset && token.End.Offset <= statement.End.Offset {
+			continue
+		}
+		if token.Kind != tokenizer.Identifier {
+			continue
+		}
+		isProperty := i > 0 && tokens[i-1].Kind == tokenizer.Punctuator && (tokens[i-1].Value == "." || tokens[i-1].Value == "?.")
+		if isProperty {
+			continue
+		}
+		if token.Value != statement.Namespace {
+			identifiers[token.Value] = struct{}{}
+			continue
+		}
+
+		isMemberAccess := i+2 < len(tokens) && tokens[i+1].Value == "." && tokens[i+2].Kind == tokenizer.Identifier
+		if !isMemberAccess {
+			return nil, nil, false
+		}
+		member := tokens[i+2].Value
+		if _, exists := barrelResolvedPaths.ResolveModuleName(resolvedPathKey, member); !exists {
+			return nil, nil, false
+		}
+		if _, exists := members[member]; !exists {
+			members[member] = struct{}{}
+			specifiers = append(specifiers, tokenizer.Specifier{Name: member, Alias: member})
+		}
+		memberEdits = append(memberEdits, textEdit{start: token.Start.Offset, end: tokens[i+2].End.Offset, text: member})
+	}
+
+	for member := range members {
+		if _, exists := identifiers[member]; exists {
+			return nil, nil, false
+		}
+	}
+	return specifiers, memberEdits, len(specifiers) > 0
 }
 
-func getModuleName(line string) string {
-	matches := TypeImportRX.FindStringSubmatch(line)
-	if len(matches) >= 2 {
-		return matches[1]
+// getResolvedPathKey returns the key of an import path of the file at path in
+// the barrel maps, along with whether the import path is an alias. Packages
+// have no key.
+func getResolvedPathKey(path string, importPath string, aliasResolver resolver.Resolver) (string, bool) {
+	if resolvedPath, isAliasPath := aliasResolver.ResolveAlias(path, importPath); isAliasPath {
+		return filepath.ToSlash(resolvedPath), true
+	}
+	if !resolver.IsRelativeImportPath(importPath) {
+		return "", false
 	}
+	barrelImportPath, _ := trimIndexFile(importPath)
+	// With rootDirs, `./api` may point to a module in another root directory
+	barrelPath := aliasResolver.RootDirsPath(path, filepath.Join(filepath.Dir(path), barrelImportPath))
+	return filepath.ToSlash(barrelPath), false
+}
 
-	matches = AliasImportRX.FindStringSubmatch(line)
-	if len(matches) >= 2 {
-		return matches[1]
+// getResolvedImportPath returns the import path of the module declaring a name
+// exported by a barrel, using the aliases of the file at path for alias imports
+// and the extension style of the barrel import path. Modules of workspace
+// packages which their `exports` don't expose can't be imported.
+func getResolvedImportPath(path string, importPath string, resolvedPathKey string, isAliasPath bool, moduleExport parser.ModuleExport, resolver resolver.Resolver) (string, bool) {
+	if moduleExport.PackageModule != "" {
+		return moduleExport.PackageModule, true
 	}
+	barrelImportPath, extension := trimIndexFile(importPath)
+	newImportPath, isImportable := getModuleImportPath(path, barrelImportPath, resolvedPathKey, isAliasPath, moduleExport, resolver)
+	return addModuleExtension(newImportPath, extension, moduleExport), isImportable
+}
 
-	return line
+func getModuleImportPath(path string, importPath string, resolvedPathKey string, isAliasPath bool, moduleExport parser.ModuleExport, resolver resolver.Resolver) (string, bool) {
+	if isAliasPath {
+		modulePath := joinCrossPlatformPaths(resolvedPathKey, moduleExport.ModulePath)
+		// Keep the alias of the import path when it also points to the module
+		newImportPath := joinCrossPlatformPaths(importPath, moduleExport.ModulePath)
+		if resolvedPath, exists := resolver.ResolveAlias(path, newImportPath); exists && filepath.ToSlash(resolvedPath) == modulePath {
+			return newImportPath, true
+		}
+		if strings.HasPrefix(importPath, "#") {
+			if subpathImportPath, exists := resolver.SubpathImportPath(path, modulePath); exists {
+				return subpathImportPath, true
+			}
+		} else if aliasPath, exists := resolver.AliasPath(path, modulePath); exists {
+			return aliasPath, true
+		}
+		if resolver.IsWorkspacePackage(importPath) {
+			// Other packages can't import the modules of @acme/ui by relative path
+			return "", false
+		}
+		// No alias covers the module, such as with `"Services$": "./src/services"`
+		return getRelativeImportPath(path, modulePath), true
+	}
+	newImportPath := joinCrossPlatformPaths(importPath, moduleExport.ModulePath)
+	if !strings.HasPrefix(newImportPath, "./") && !strings.HasPrefix(newImportPath, "../") {
+		newImportPath = "./" + newImportPath
+	}
+	return newImportPath, true
+}
+
+// trimIndexFile returns the import path of a barrel directory imported through
+// its index file, such as `./ui` for `./ui/index.js`, along with the extension
+// the index file is imported with.
+func trimIndexFile(importPath string) (string, string) {
+	slashIndex := strings.LastIndex(importPath, "/")
+	extension, isIndexFile := strings.CutPrefix(importPath[slashIndex+1:], "index")
+	if slashIndex < 0 || !isIndexFile || (extension != "" && !isModuleExtension(extension)) {
+		return importPath, ""
+	}
+	return importPath[:slashIndex], extension
+}
+
+// emittedExtensions are the extensions of the JavaScript files emitted for
+// modules, which Node16 module resolution expects in import paths.
+var emittedExtensions = map[string]string{
+	".ts": ".js", ".tsx": ".js", ".d.ts": ".js", ".js": ".js", ".jsx": ".jsx",
+	".mts": ".mjs", ".d.mts": ".mjs", ".mjs": ".mjs",
+	".cts": ".cjs", ".d.cts": ".cjs", ".cjs": ".cjs",
+}
+
+func isModuleExtension(extension string) bool {
+	_, exists := emittedExtensions[extension]
+	return exists
+}
+
+// addModuleExtension adds to the import path of a module the extension style
+// of the barrel import: `./ui/index.js` is replaced by `./ui/button/Button.js`
+// as Node16 module resolution requires, and `./ui/index.ts` by
+// `./ui/button/Button.tsx` with allowImportingTsExtensions. ES and CommonJS
+// modules such as `legacy.mts` are never resolved without their extension, so
+// `./ui` is replaced by `./ui/legacy.mjs`.
+func addModuleExtension(importPath string, barrelExtension string, moduleExport parser.ModuleExport) string {
+	if moduleExport.ModuleFilePath == "" {
+		return importPath
+	}
+	fileName := filepath.Base(moduleExport.ModuleFilePath)
+	extension := parser.ModuleExtension(fileName)
+	if emittedExtension := emittedExtensions[extension]; barrelExtension == "" && emittedExtension != ".mjs" && emittedExtension != ".cjs" {
+		return importPath
+	}
+	if strings.TrimSuffix(fileName, extension) == "index" {
+		// Directory index files are only looked up for import paths without extension
+		importPath += "/index"
+	}
+	// TypeScript extensions are kept with allowImportingTsExtensions, but declaration files can't be imported
+	isTSStyle := emittedExtensions[barrelExtension] != barrelExtension
+	if emittedExtension, exists := emittedExtensions[extension]; exists && (!isTSStyle || strings.HasPrefix(extension, ".d.")) {
+		extension = emittedExtension
+	}
+	return importPath + extension
+}
+
+// getRelativeImportPath returns the relative import path of the module at
+// modulePath from the file at path.
+func getRelativeImportPath(path string, modulePath string) string {
+	relativePath, err := filepath.Rel(filepath.Dir(path), filepath.FromSlash(modulePath))
+	if err != nil {
+		return modulePath
+	}
+	relativePath = filepath.ToSlash(relativePath)
+	if !strings.HasPrefix(relativePath, "../") {
+		relativePath = "./" + relativePath
+	}
+	return relativePath
+}
+
+// formatImportStatement formats the import clause of specifiers imported from
+// the same module, using the first default specifier as the default import.
+func formatImportStatement(format statementFormat, isTypeImport bool, specifiers []tokenizer.Specifier) string {
+	defaultName := ""
+	importNames := []clauseSpecifier{}
+	for _, specifier := range specifiers {
+		if isTypeImport {
+			specifier.IsType = false
+		}
+		// A type-only import can't specify both a default import and named bindings
+		canBeDefault := !isTypeImport || len(specifiers) == 1
+		if specifier.Name == "default" && defaultName == "" && !specifier.IsType && canBeDefault {
+			defaultName = specifier.Alias
+			continue
+		}
+		importNames = append(importNames, clauseSpecifier{alias: specifier.Alias, text: formatSpecifier(specifier)})
+	}
+
+	newImportStatement := "import "
+	if isTypeImport {
+		newImportStatement += "type "
+	}
+	if defaultName != "" {
+		newImportStatement += defaultName
+		if len(importNames) > 0 {
+			newImportStatement += ", "
+		}
+	}
+	if len(importNames) > 0 {
+		newImportStatement += format.formatClause(importNames)
+	}
+	return newImportStatement
+}
+
+// formatExportStatement formats the export clause of specifiers re-exported
+// from the same module.
+func formatExportStatement(format statementFormat, isTypeExport bool, specifiers []tokenizer.Specifier) string {
+	exportNames := []clauseSpecifier{}
+	for _, specifier := range specifiers {
+		if isTypeExport {
+			specifier.IsType = false
+		}
+		exportNames = append(exportNames, clauseSpecifier{alias: specifier.Alias, text: formatSpecifier(specifier)})
+	}
+
+	newExportStatement := "export "
+	if isTypeExport {
+		newExportStatement += "type "
+	}
+	return newExportStatement + format.formatClause(exportNames)
+}
+
+// formatRequireStatements formats `const { a, b: c } = require('module')` for
+// specifiers required from the same module, namespaces being required on their own.
+func formatRequireStatements(statement tokenizer.Import, format statementFormat, modulePath string, specifiers []tokenizer.Specifier) []string {
+	requireCall := fmt.Sprintf(" = require(%s%s%s)", statement.Quote, modulePath, statement.Quote)
+	if statement.Semicolon {
+		requireCall += ";"
+	}
+
+	requireStatements := []string{}
+	properties := []clauseSpecifier{}
+	for _, specifier := range specifiers {
+		switch {
+		case specifier.Name == "*":
+			requireStatements = append(requireStatements, statement.Require+" "+specifier.Alias+requireCall)
+		case specifier.Alias != specifier.Name:
+			properties = append(properties, clauseSpecifier{alias: specifier.Alias, text: specifier.Name + ": " + specifier.Alias})
+		default:
+			properties = append(properties, clauseSpecifier{alias: specifier.Alias, text: specifier.Name})
+		}
+	}
+	if len(properties) > 0 {
+		propertiesStatement := statement.Require + " " + format.formatClause(properties) + requireCall
+		requireStatements = append([]string{propertiesStatement}, requireStatements...)
+	}
+	return requireStatements
+}
+
+// formatSpecifier formats a specifier back to `type Name as Alias`
+func formatSpecifier(specifier tokenizer.Specifier) string {
+	formatted := specifier.Name
+	if specifier.Alias != specifier.Name {
+		formatted += " as " + specifier.Alias
+	}
+	if specifier.IsType {
+		formatted = "type " + formatted
+	}
+	return formatted
 }
 
 func joinCrossPlatformPaths(elem ...string) string {
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/nergie/no-barrel-file/internal/tests"
@@ -23,6 +25,132 @@ func TestReplaceCommand(t *testing.T) {
 	output, err := tests.ExecuteCommand(rootCmd, "replace", "--root-path", initialRootPath, "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json")
 
 	assert.NoError(t, err)
-	assert.Contains(t, output, "4 files updated\n")
+	assert.Contains(t, output, "13 files updated\n")
+	assert.Contains(t, output, "Ambiguous import of AMBIGUOUS_CONST from @barrel-basic in "+filepath.Join(initialRootPath, "ambiguous-barrel-in-use.ts")+", exported by ambiguous-a, ambiguous-b\n")
 	tests.CompareDirs(t, initialRootPath, expectedRootPath)
 }
+
+func TestReplaceCommandAmbiguousReExports(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDirPath := "../tests/data/input"
+	initialRootPath := filepath.Join(tmpDir, inputDirPath)
+	defer os.RemoveAll(tmpDir)
+	tests.CopyDir(inputDirPath, initialRootPath)
+	ambiguousPath := filepath.Join(initialRootPath, "ambiguous-re-export.ts")
+	os.WriteFile(ambiguousPath, []byte(`export { AMBIGUOUS_CONST } from "@barrel-basic";
+
+export async function load() {
+  const { AMBIGUOUS_CONST: value } = await import("@barrel-basic");
+  return value;
+}
+`), 0644)
+
+	output, err := tests.ExecuteCommand(rootCmd, "replace", "--root-path", initialRootPath, "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json", "--target-path", "ambiguous-re-export.ts", "--dynamic-imports", "--dry-run")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(output, "Ambiguous import of AMBIGUOUS_CONST from @barrel-basic in "+ambiguousPath+", exported by ambiguous-a, ambiguous-b\n"))
+}
+
+func TestReplaceCommandDynamicImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDirPath := "../tests/data/input"
+	initialRootPath := filepath.Join(tmpDir, inputDirPath)
+	defer os.RemoveAll(tmpDir)
+	tests.CopyDir(inputDirPath, initialRootPath)
+
+	output, err := tests.ExecuteCommand(rootCmd, "replace", "--root-path", initialRootPath, "--ignore-paths", "ignored", "--alias-config-path", "tsconfig.json", "--target-path", "dynamic-barrel-in-use.ts", "--dynamic-imports")
+
+	assert.NoError(t, err)
+	assert.Contains(t, output, "1 files updated\n")
+	actual, _ := os.ReadFile(filepath.Join(initialRootPath, "dynamic-barrel-in-use.ts"))
+	expected, _ := os.ReadFile("../tests/data/expected-dynamic-imports/dynamic-barrel-in-use.ts")
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestReplaceCommandFixtures(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inputDir    string
+		expectedDir string
+		args        []string
+		output      string
+	}{
+		{"NearestAliasConfig", "input-monorepo", "expected-monorepo", []string{"--nearest-alias-config"}, "2 files updated\n"},
+		{"CommonJS", "input-commonjs", "expected-commonjs", nil, "1 files updated\n"},
+		{"ImportMap", "input-import-map", "expected-import-map", nil, "2 files updated\n"},
+		{"SubpathImports", "input-subpath-imports", "expected-subpath-imports", nil, "1 files updated\n"},
+		{"ToolAliases", "input-tool-aliases", "expected-tool-aliases", nil, "1 files updated\n"},
+		{"ModuleExtensions", "input-node16", "expected-node16", nil, "2 files updated\n"},
+		{"RootDirs", "input-root-dirs", "expected-root-dirs", []string{"--alias-config-path", "tsconfig.json"}, "1 files updated\n"},
+		{"Workspaces", "input-workspaces", "expected-workspaces", nil, "1 files updated\n"},
+		{"Formatting", "input-formatting", "expected-formatting", nil, "3 files updated\n"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			initialRootPath := filepath.Join(tmpDir, "input")
+			expectedRootPath := filepath.Join(tmpDir, "expected")
+			tests.CopyDir(filepath.Join("../tests/data", testCase.inputDir), initialRootPath)
+			tests.CopyDir(filepath.Join("../tests/data", testCase.expectedDir), expectedRootPath)
+
+			args := append([]string{"replace", "--root-path", initialRootPath}, testCase.args...)
+			output, err := tests.ExecuteCommand(rootCmd, args...)
+
+			assert.NoError(t, err)
+			assert.Contains(t, output, testCase.output)
+			tests.CompareDirs(t, initialRootPath, expectedRootPath)
+		})
+	}
+}
+
+func TestReplaceCommandDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDirPath := "../tests/data/input-node16"
+	initialRootPath := filepath.Join(tmpDir, inputDirPath)
+	unchangedRootPath := filepath.Join(tmpDir, "unchanged")
+	defer os.RemoveAll(tmpDir)
+	tests.CopyDir(inputDirPath, initialRootPath)
+	tests.CopyDir(inputDirPath, unchangedRootPath)
+
+	output, err := tests.ExecuteCommand(rootCmd, "replace", "--root-path", initialRootPath, "--alias-config-path", "tsconfig.json", "--dry-run")
+
+	assert.NoError(t, err)
+	assert.Contains(t, output, "diff --git a/src/app.ts b/src/app.ts\n--- a/src/app.ts\n+++ b/src/app.ts\n@@ -1,4 +1,6 @@\n")
+	assert.Contains(t, output, "-import { legacy } from \"./components\";\n+import { Button } from \"./components/button/Button.tsx\";\n")
+	assert.Contains(t, output, "2 files would be updated\n")
+	tests.CompareDirs(t, initialRootPath, unchangedRootPath)
+
+	output, err = tests.ExecuteCommand(rootCmd, "replace", "--root-path", initialRootPath, "--alias-config-path", "tsconfig.json", "--dry-run", "--output-patch", "changes.patch")
+
+	assert.NoError(t, err)
+	assert.NotContains(t, output, "diff --git")
+	patchPath := filepath.Join(initialRootPath, "changes.patch")
+	patch, err := os.ReadFile(patchPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(patch), "diff --git a/src/page.ts b/src/page.ts\n")
+	if _, err := exec.LookPath("git"); err == nil {
+		git := exec.Command("git", "apply", "--check", "changes.patch")
+		git.Dir = initialRootPath
+		gitOutput, err := git.CombinedOutput()
+		assert.NoError(t, err, string(gitOutput))
+	}
+	os.Remove(patchPath)
+	tests.CompareDirs(t, initialRootPath, unchangedRootPath)
+}
+
+func TestReplaceCommandErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDirPath := "../tests/data/input-root-dirs"
+	rootPath := filepath.Join(tmpDir, inputDirPath)
+	defer os.RemoveAll(tmpDir)
+	tests.CopyDir(inputDirPath, rootPath)
+
+	output, err := tests.ExecuteCommand(rootCmd, "replace", "--root-path", rootPath, "--target-path", "does-not-exist", "--dry-run")
+	assert.ErrorContains(t, err, "unable to replace the target path")
+	assert.NotContains(t, output, "files would be updated")
+
+	os.WriteFile(filepath.Join(rootPath, "tsconfig.json"), []byte(`{ "compilerOptions": { "rootDirs": ["src", "generated"] `), 0644)
+	output, err = tests.ExecuteCommand(rootCmd, "replace", "--root-path", rootPath, "--alias-config-path", "tsconfig.json", "--dry-run")
+	assert.ErrorContains(t, err, "unable to load the tsconfig "+filepath.Join(rootPath, "tsconfig.json"))
+	assert.Contains(t, output, "files would be updated\n")
+}
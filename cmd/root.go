@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -33,11 +34,33 @@ var (
 	}
 )
 
+// exitError ends the CLI with an exit code other than 1, printing its message if any
+type exitError struct {
+	code    int
+	message string
+}
+
+func (err exitError) Error() string {
+	return err.message
+}
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+	var exit exitError
+	if errors.As(err, &exit) {
+		if exit.message != "" {
+			fmt.Println(exit.message)
+		}
+		os.Exit(exit.code)
+	}
+	fmt.Println(err)
+	if cmd == checkCmd {
+		os.Exit(checkErrorExitCode)
 	}
+	os.Exit(1)
 }
 
 func init() {
@@ -46,6 +69,7 @@ func init() {
 	cmd_flag.AddExtensions(rootCmd)
 	cmd_flag.AddRootPath(rootCmd)
 
+	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(countCmd)
 	rootCmd.AddCommand(displayCmd)
 	rootCmd.AddCommand(replaceCmd)
@@ -0,0 +1,102 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Entry is a symbol imported from a barrel by a file, both paths being
+// relative to the root path. Line numbers are left out so that a baseline
+// doesn't go stale when lines shift.
+type Entry struct {
+	File   string `json:"file"`
+	Barrel string `json:"barrel"`
+	Symbol string `json:"symbol"`
+}
+
+// Baseline is the set of barrel imports tolerated by the check command
+type Baseline struct {
+	entries map[Entry]struct{}
+}
+
+// baselineFile is the JSON format of a baseline file
+type baselineFile struct {
+	Entries []Entry `json:"entries"`
+}
+
+func New(entries []Entry) Baseline {
+	baseline := Baseline{entries: make(map[Entry]struct{})}
+	for _, entry := range entries {
+		baseline.entries[entry] = struct{}{}
+	}
+	return baseline
+}
+
+// Read returns the baseline of the file at path
+func Read(path string) (Baseline, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return Baseline{}, fmt.Errorf("unable to read the baseline: %w", err)
+	}
+	var file baselineFile
+	if err := json.Unmarshal(contents, &file); err != nil {
+		return Baseline{}, fmt.Errorf("unable to parse the baseline %s: %w", path, err)
+	}
+	return New(file.Entries), nil
+}
+
+// Write writes the baseline to the file at path, its entries being sorted so
+// that the file only changes with them.
+func (baseline Baseline) Write(path string) error {
+	contents, err := json.MarshalIndent(baselineFile{Entries: baseline.Entries()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(contents, '\n'), 0644); err != nil {
+		return fmt.Errorf("unable to write the baseline: %w", err)
+	}
+	return nil
+}
+
+// Entries returns the entries of the baseline, sorted by file, barrel and symbol
+func (baseline Baseline) Entries() []Entry {
+	entries := make([]Entry, 0, len(baseline.entries))
+	for entry := range baseline.entries {
+		entries = append(entries, entry)
+	}
+	sortEntries(entries)
+	return entries
+}
+
+func (baseline Baseline) Contains(entry Entry) bool {
+	_, exists := baseline.entries[entry]
+	return exists
+}
+
+// StaleEntries returns the entries of the baseline which are not in found,
+// the barrel imports they tolerated having been removed since.
+func (baseline Baseline) StaleEntries(found []Entry) []Entry {
+	foundEntries := New(found)
+	staleEntries := []Entry{}
+	for entry := range baseline.entries {
+		if !foundEntries.Contains(entry) {
+			staleEntries = append(staleEntries, entry)
+		}
+	}
+	sortEntries(staleEntries)
+	return staleEntries
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].File != entries[j].File {
+			return entries[i].File < entries[j].File
+		}
+		if entries[i].Barrel != entries[j].Barrel {
+			return entries[i].Barrel < entries[j].Barrel
+		}
+		return entries[i].Symbol < entries[j].Symbol
+	})
+}
@@ -70,6 +70,28 @@ func AliasConfigPath(cmd *cobra.Command) string {
 	return cmd.Flags().Lookup("alias-config-path").Value.String()
 }
 
+func AddNearestAliasConfig(cmd *cobra.Command) {
+	cmd.Flags().BoolP(
+		"nearest-alias-config", "n", false, "Resolve the aliases of each file with its closest 'tsconfig.json' or 'jsconfig.json', for monorepos with a config per package.")
+}
+
+func NearestAliasConfig(cmd *cobra.Command) bool {
+	isEnabled, err := cmd.Flags().GetBool("nearest-alias-config")
+	if err != nil {
+		return false
+	}
+	return isEnabled
+}
+
+func AddImportMapPath(cmd *cobra.Command) {
+	cmd.Flags().StringP(
+		"import-map", "m", "", "Relative path to an import map or 'deno.json' for alias resolution. Defaults to the 'deno.json' or 'deno.jsonc' of the root path.")
+}
+
+func ImportMapPath(cmd *cobra.Command) string {
+	return cmd.Flags().Lookup("import-map").Value.String()
+}
+
 func AddBarrelPath(cmd *cobra.Command) {
 	cmd.Flags().StringP(
 		"barrel-path", "b", ".", "Relative path of a barrel file import to replaced.")
@@ -90,3 +112,63 @@ func Verbose(cmd *cobra.Command) bool {
 	}
 	return isVerbose
 }
+
+func AddDynamicImports(cmd *cobra.Command) {
+	cmd.Flags().BoolP("dynamic-imports", "d", false, "Replace destructured dynamic imports of barrel files, such as const { a } = await import('barrel').")
+}
+
+func DynamicImports(cmd *cobra.Command) bool {
+	isEnabled, err := cmd.Flags().GetBool("dynamic-imports")
+	if err != nil {
+		return false
+	}
+	return isEnabled
+}
+
+func AddDryRun(cmd *cobra.Command) {
+	cmd.Flags().Bool("dry-run", false, "Print the changes as a unified diff instead of writing files.")
+}
+
+func DryRun(cmd *cobra.Command) bool {
+	isEnabled, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return false
+	}
+	return isEnabled
+}
+
+func AddOutputPatch(cmd *cobra.Command) {
+	cmd.Flags().String(
+		"output-patch", "", "Relative path of a file to write the changes to as a patch, which can be applied with 'git apply' from the root path.")
+}
+
+func OutputPatch(cmd *cobra.Command) string {
+	return cmd.Flags().Lookup("output-patch").Value.String()
+}
+
+func AddBaseline(cmd *cobra.Command) {
+	cmd.Flags().String(
+		"baseline", "", "Relative path to a baseline file of barrel imports to tolerate, written by '--write-baseline'.")
+}
+
+func Baseline(cmd *cobra.Command) string {
+	return cmd.Flags().Lookup("baseline").Value.String()
+}
+
+func AddWriteBaseline(cmd *cobra.Command) {
+	cmd.Flags().String(
+		"write-baseline", "", "Relative path of a baseline file to record the current barrel imports to.")
+}
+
+func WriteBaseline(cmd *cobra.Command) string {
+	return cmd.Flags().Lookup("write-baseline").Value.String()
+}
+
+func AddDiffBase(cmd *cobra.Command) {
+	cmd.Flags().String(
+		"diff-base", "", "Git revision such as 'origin/main' to only report the barrel imports on lines added since its merge base with HEAD.")
+}
+
+func DiffBase(cmd *cobra.Command) string {
+	return cmd.Flags().Lookup("diff-base").Value.String()
+}
@@ -9,14 +9,18 @@ import (
 
 type BarrelResolvedPath struct {
 	ExistenceMap      map[string]struct{}
-	ModuleResolverMap map[string]string
+	ModuleResolverMap map[string]parser.ModuleExport
+	AmbiguousMap      map[string][]string
+	Resolver          resolver.Resolver
 }
 
 func NewBarrelResolvedPath(parser parser.Parser, resolver resolver.Resolver) BarrelResolvedPath {
-	barrelPathExistenceMap, barrelModuleResolverMap := parser.BarrelMaps(resolver)
+	barrelPathExistenceMap, barrelModuleResolverMap, barrelAmbiguousExportMap := parser.BarrelMaps()
 	return BarrelResolvedPath{
 		ExistenceMap:      barrelPathExistenceMap,
 		ModuleResolverMap: barrelModuleResolverMap,
+		AmbiguousMap:      barrelAmbiguousExportMap,
+		Resolver:          resolver,
 	}
 }
 
@@ -25,7 +29,14 @@ func (b *BarrelResolvedPath) IsResolved(path string) bool {
 	return exists
 }
 
-func (b *BarrelResolvedPath) ResolveModuleName(path string, moduleName string) (string, bool) {
-	resolvedPath, exists := b.ModuleResolverMap[filepath.Join(path, moduleName)]
-	return resolvedPath, exists
+func (b *BarrelResolvedPath) ResolveModuleName(path string, moduleName string) (parser.ModuleExport, bool) {
+	moduleExport, exists := b.ModuleResolverMap[filepath.Join(path, moduleName)]
+	return moduleExport, exists
+}
+
+// AmbiguousModulePaths returns the modules exporting moduleName when the barrel
+// at path exports it ambiguously through several `export *`.
+func (b *BarrelResolvedPath) AmbiguousModulePaths(path string, moduleName string) ([]string, bool) {
+	modulePaths, exists := b.AmbiguousMap[filepath.Join(path, moduleName)]
+	return modulePaths, exists
 }
@@ -0,0 +1,200 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines around the changes of a hunk
+const contextLines = 3
+
+// lineOperation is a line of a diff: kept (' '), deleted ('-') or inserted ('+')
+type lineOperation struct {
+	kind byte
+	line string
+}
+
+// Unified returns the git-compatible unified diff turning before into after
+// for the file at path, relative to the directory the patch is applied in.
+// It is empty when both contents are equal.
+func Unified(path string, before string, after string) string {
+	if before == after {
+		return ""
+	}
+	operations := diffLines(splitLines(before), splitLines(after))
+
+	var patch strings.Builder
+	fmt.Fprintf(&patch, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", path, path, path, path)
+	// Index of the first old and new line of each operation
+	oldLines := make([]int, len(operations)+1)
+	newLines := make([]int, len(operations)+1)
+	for i, operation := range operations {
+		oldLines[i+1], newLines[i+1] = oldLines[i], newLines[i]
+		if operation.kind != '+' {
+			oldLines[i+1]++
+		}
+		if operation.kind != '-' {
+			newLines[i+1]++
+		}
+	}
+
+	for i := 0; i < len(operations); {
+		if operations[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Changes less than two contexts apart are merged in one hunk
+		lastChange := i
+		for j := i; j < len(operations) && j-lastChange <= 2*contextLines; j++ {
+			if operations[j].kind != ' ' {
+				lastChange = j
+			}
+		}
+		start := max(i-contextLines, 0)
+		end := min(lastChange+contextLines+1, len(operations))
+		fmt.Fprintf(&patch, "@@ -%s +%s @@\n",
+			formatRange(oldLines[start], oldLines[end]-oldLines[start]),
+			formatRange(newLines[start], newLines[end]-newLines[start]))
+		for _, operation := range operations[start:end] {
+			patch.WriteByte(operation.kind)
+			patch.WriteString(operation.line)
+			if !strings.HasSuffix(operation.line, "\n") {
+				patch.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return patch.String()
+}
+
+// formatRange formats the lines of a hunk as git does: `3` for one line
+// starting at line 3, `3,2` for two lines, and `2,0` for no line after line 2.
+func formatRange(start int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits contents after each `\n`, keeping `\r` in the lines of
+// CRLF files so that they are written back unchanged.
+func splitLines(contents string) []string {
+	lines := strings.SplitAfter(contents, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script from a to b, found with the
+// Myers algorithm after trimming the lines both start and end with.
+func diffLines(a []string, b []string) []lineOperation {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	operations := []lineOperation{}
+	for _, line := range a[:prefix] {
+		operations = append(operations, lineOperation{' ', line})
+	}
+	operations = append(operations, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		operations = append(operations, lineOperation{' ', line})
+	}
+	return operations
+}
+
+func myersDiff(a []string, b []string) []lineOperation {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v[k] for k in [-d-1, d+1] before the edits of step d
+	trace := [][]int{}
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			x := v[offset+k-1] + 1
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack follows the trace of the Myers algorithm from the end of a and b
+// back to their start, and returns the operations in order.
+func backtrack(a []string, b []string, trace [][]int) []lineOperation {
+	operations := []lineOperation{}
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		previousK := k - 1
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			previousK = k + 1
+		}
+		previousX := v(previousK)
+		previousY := previousX - previousK
+		for x > previousX && y > previousY {
+			x--
+			y--
+			operations = append(operations, lineOperation{' ', a[x]})
+		}
+		if d > 0 {
+			if x == previousX {
+				y--
+				operations = append(operations, lineOperation{'+', b[y]})
+			} else {
+				x--
+				operations = append(operations, lineOperation{'-', a[x]})
+			}
+		}
+	}
+	for i, j := 0, len(operations)-1; i < j; i, j = i+1, j-1 {
+		operations[i], operations[j] = operations[j], operations[i]
+	}
+	return operations
+}
+
+// Colorize colors a unified diff for a terminal, as git does
+func Colorize(patch string) string {
+	var colored strings.Builder
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		content := strings.TrimSuffix(line, "\n")
+		color := ""
+		switch {
+		case strings.HasPrefix(content, "diff --git "), strings.HasPrefix(content, "--- "), strings.HasPrefix(content, "+++ "):
+			color = "\x1b[1m"
+		case strings.HasPrefix(content, "@@"):
+			color = "\x1b[36m"
+		case strings.HasPrefix(content, "-"):
+			color = "\x1b[31m"
+		case strings.HasPrefix(content, "+"):
+			color = "\x1b[32m"
+		}
+		if color == "" || content == "" {
+			colored.WriteString(line)
+			continue
+		}
+		colored.WriteString(color + content + "\x1b[m" + line[len(content):])
+	}
+	return colored.String()
+}
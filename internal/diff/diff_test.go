@@ -0,0 +1,46 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	before := "import { Button, Card } from './components';\n\nconst a = 1;\nconst b = 2;\nconst c = 3;\nconst d = 4;\nconst e = 5;\nconst f = 6;\nconst g = 7;\nconst h = 8;\nexport { a }"
+	after := strings.Replace(before, "import { Button, Card } from './components';", "import { Button } from './components/button';\nimport { Card } from './components/card';", 1)
+	after = strings.Replace(after, "export { a }", "export { a, h }", 1)
+
+	assert.Equal(t, `diff --git a/src/app.ts b/src/app.ts
+--- a/src/app.ts
++++ b/src/app.ts
+@@ -1,4 +1,5 @@
+-import { Button, Card } from './components';
++import { Button } from './components/button';
++import { Card } from './components/card';
+ 
+ const a = 1;
+ const b = 2;
+@@ -8,4 +9,4 @@
+ const f = 6;
+ const g = 7;
+ const h = 8;
+-export { a }
+\ No newline at end of file
++export { a, h }
+\ No newline at end of file
+`, Unified("src/app.ts", before, after))
+	assert.Equal(t, "", Unified("src/app.ts", before, before))
+}
+
+func TestUnifiedSingleLine(t *testing.T) {
+	assert.Equal(t, `diff --git a/a.ts b/a.ts
+--- a/a.ts
++++ b/a.ts
+@@ -1 +1,2 @@
+-import { a, b } from './lib';
++import { a } from './lib/a';
++import { b } from './lib/b';
+`, Unified("a.ts", "import { a, b } from './lib';\n", "import { a } from './lib/a';\nimport { b } from './lib/b';\n"))
+}
@@ -0,0 +1,121 @@
+package gitdiff
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// lineRange is a range of lines, starting at 1 and including end
+type lineRange struct {
+	start int
+	end   int
+}
+
+// AddedLines are the lines added to the files of a directory since a git
+// revision, by file path relative to the directory.
+type AddedLines struct {
+	ranges map[string][]lineRange
+}
+
+// hunkHeaderRegexp matches the new lines of a hunk, `+12,3` in `@@ -10,2 +12,3 @@`
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// Added returns the lines added to the working tree of the git repository at
+// dir since its merge base with base, such as `origin/main`, so that changes
+// made to base since then don't count. Untracked files are added as a whole.
+func Added(dir string, base string) (AddedLines, error) {
+	mergeBase, err := runGit(dir, "merge-base", base, "HEAD")
+	if err != nil {
+		return AddedLines{}, err
+	}
+	diff, err := runGit(dir, "-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "--unified=0", "--relative", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return AddedLines{}, err
+	}
+	addedLines := AddedLines{ranges: parseDiff(diff)}
+
+	untrackedFiles, err := runGit(dir, "-c", "core.quotePath=false", "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return AddedLines{}, err
+	}
+	for _, path := range strings.Split(untrackedFiles, "\n") {
+		if path != "" {
+			addedLines.ranges[path] = []lineRange{{start: 1, end: math.MaxInt}}
+		}
+	}
+	return addedLines, nil
+}
+
+// Overlaps reports whether any line from start to end of the file at path was added
+func (addedLines AddedLines) Overlaps(path string, start int, end int) bool {
+	for _, added := range addedLines.ranges[path] {
+		if added.start <= end && start <= added.end {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDiff returns the ranges of lines added by a diff without context lines,
+// by path of the changed files.
+func parseDiff(diff string) map[string][]lineRange {
+	ranges := make(map[string][]lineRange)
+	path := ""
+	// Added lines starting with `++ ` must not be taken for file headers
+	isFileHeader := false
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			isFileHeader = true
+			continue
+		}
+		if newPath, isNewPath := strings.CutPrefix(line, "+++ "); isNewPath && isFileHeader {
+			path = ""
+			// Deleted files are diffed with /dev/null
+			if unquotedPath, err := strconv.Unquote(newPath); err == nil {
+				newPath = unquotedPath
+			}
+			if filePath, isFilePath := strings.CutPrefix(newPath, "b/"); isFilePath {
+				path = filePath
+			}
+			continue
+		}
+		match := hunkHeaderRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		isFileHeader = false
+		if path == "" {
+			continue
+		}
+		start, _ := strconv.Atoi(match[1])
+		count := 1
+		if match[2] != "" {
+			count, _ = strconv.Atoi(match[2])
+		}
+		if count > 0 {
+			ranges[path] = append(ranges[path], lineRange{start: start, end: start + count - 1})
+		}
+	}
+	return ranges
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), message)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,46 @@
+package gitdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiff(t *testing.T) {
+	ranges := parseDiff(`diff --git a/src/app.ts b/src/app.ts
+index 3b18e51..a9c1f2e 100644
+--- a/src/app.ts
++++ b/src/app.ts
+@@ -1 +1,2 @@
+-import { Button } from "./components";
++import { Button, Card } from "./components";
++import { theme } from "./theme";
+@@ -10,0 +12 @@ export const App = () => null;
++++ counter;
+@@ -20,3 +22,0 @@
+-removed();
+-removed();
+-removed();
+diff --git a/src/old.ts b/src/old.ts
+deleted file mode 100644
+--- a/src/old.ts
++++ /dev/null
+@@ -1 +0,0 @@
+-import { Card } from "./components";
+diff --git "a/src/t\tab.ts" "b/src/t\tab.ts"
+new file mode 100644
+--- /dev/null
++++ "b/src/t\tab.ts"
+@@ -0,0 +1 @@
++import { Card } from "./components";
+`)
+
+	assert.Equal(t, map[string][]lineRange{
+		"src/app.ts":   {{start: 1, end: 2}, {start: 12, end: 12}},
+		"src/t\tab.ts": {{start: 1, end: 1}},
+	}, ranges)
+	addedLines := AddedLines{ranges: ranges}
+	assert.True(t, addedLines.Overlaps("src/app.ts", 2, 4))
+	assert.False(t, addedLines.Overlaps("src/app.ts", 3, 11))
+	assert.False(t, addedLines.Overlaps("src/old.ts", 1, 1))
+}
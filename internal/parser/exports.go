@@ -0,0 +1,318 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nergie/no-barrel-file/internal/resolver"
+	"github.com/nergie/no-barrel-file/internal/tokenizer"
+)
+
+// exportedBinding is the module where an exported name is declared and the
+// name this module exports it with. Names re-exported from packages have no
+// file path but the package module specifier.
+type exportedBinding struct {
+	filePath      string
+	packageModule string
+	name          string
+}
+
+// module returns the binding of the module itself, without the exported name
+func (binding exportedBinding) module() exportedBinding {
+	return exportedBinding{filePath: binding.filePath, packageModule: binding.packageModule}
+}
+
+// exportTable lists the names exported by a module. Names exported by several
+// modules through `export *` are ambiguous and excluded from bindings, as
+// TypeScript does.
+type exportTable struct {
+	bindings  map[string]exportedBinding
+	ambiguous map[string][]exportedBinding // modules exporting an ambiguous name
+}
+
+type importedBinding struct {
+	source string
+	name   string
+}
+
+// moduleIndexer lists the names exported by modules, following re-exports
+// down to the module declaring each name.
+type moduleIndexer struct {
+	extensions []string
+	rootDirs   RootDirs
+	modules    map[string]tokenizer.Module
+	exports    map[string]exportTable
+	stack      []string
+	partial    map[string]struct{}
+}
+
+func newModuleIndexer(extensions []string, rootDirs RootDirs) *moduleIndexer {
+	return &moduleIndexer{
+		extensions: extensions,
+		rootDirs:   rootDirs,
+		modules:    make(map[string]tokenizer.Module),
+		exports:    make(map[string]exportTable),
+		partial:    make(map[string]struct{}),
+	}
+}
+
+func (indexer *moduleIndexer) moduleExports(filePath string) exportTable {
+	if exports, exists := indexer.exports[filePath]; exists {
+		return exports
+	}
+	for i, visitingPath := range indexer.stack {
+		if visitingPath == filePath {
+			// Circular re-exports: modules visited since filePath only get partial results
+			for _, dependentPath := range indexer.stack[i+1:] {
+				indexer.partial[dependentPath] = struct{}{}
+			}
+			return exportTable{}
+		}
+	}
+
+	indexer.stack = append(indexer.stack, filePath)
+	exports := indexer.collectExports(filePath)
+	indexer.stack = indexer.stack[:len(indexer.stack)-1]
+	if _, isPartial := indexer.partial[filePath]; isPartial {
+		delete(indexer.partial, filePath)
+	} else {
+		indexer.exports[filePath] = exports
+	}
+	return exports
+}
+
+func (indexer *moduleIndexer) collectExports(filePath string) exportTable {
+	exports := make(map[string]exportedBinding)
+	module, exists := indexer.module(filePath)
+	if !exists {
+		return exportTable{bindings: exports}
+	}
+
+	importedBindings := getImportedBindings(module)
+	starSources := []string{}
+	for _, export := range module.Exports {
+		switch {
+		case export.IsStar && export.Namespace == "":
+			starSources = append(starSources, export.Source)
+		case export.IsStar:
+			// export * as ns from './module'
+			if binding, exists := indexer.resolveModule(filePath, export.Source); exists {
+				binding.name = "*"
+				exports[export.Namespace] = binding
+			}
+		case export.Source != "":
+			for _, specifier := range export.Specifiers {
+				if binding, exists := indexer.resolveExport(filePath, export.Source, specifier.Name); exists {
+					exports[specifier.Alias] = binding
+				}
+			}
+		case export.IsDefault:
+			exports["default"] = exportedBinding{filePath: filePath, name: "default"}
+		default:
+			for _, name := range export.Declarations {
+				exports[name] = exportedBinding{filePath: filePath, name: name}
+			}
+			for _, specifier := range export.Specifiers {
+				// import { a } from './a'; export { a as b };
+				if imported, isImported := importedBindings[specifier.Name]; isImported {
+					if binding, exists := indexer.resolveExport(filePath, imported.source, imported.name); exists {
+						exports[specifier.Alias] = binding
+					}
+					continue
+				}
+				// const a = 1; export { a as b };
+				exports[specifier.Alias] = exportedBinding{filePath: filePath, name: specifier.Alias}
+			}
+		}
+	}
+
+	// Explicit exports take precedence over `export *`, and a name exported by
+	// several `export *` with different bindings is ambiguous.
+	starExports := make(map[string]exportedBinding)
+	ambiguous := make(map[string][]exportedBinding)
+	for _, source := range starSources {
+		sourcePath, exists := resolveModulePath(filePath, source, indexer.extensions, indexer.rootDirs(filePath))
+		if !exists {
+			continue
+		}
+		sourceExports := indexer.moduleExports(sourcePath)
+		for _, name := range sortedKeys(sourceExports.ambiguous) {
+			if _, isExplicit := exports[name]; !isExplicit {
+				ambiguous[name] = appendUnique(ambiguous[name], sourceExports.ambiguous[name]...)
+			}
+		}
+		for _, name := range sortedKeys(sourceExports.bindings) {
+			binding := sourceExports.bindings[name]
+			if _, isExplicit := exports[name]; isExplicit || name == "default" {
+				continue
+			}
+			if _, isAmbiguous := ambiguous[name]; isAmbiguous {
+				ambiguous[name] = appendUnique(ambiguous[name], binding.module())
+				continue
+			}
+			if existing, exists := starExports[name]; exists && existing != binding {
+				ambiguous[name] = appendUnique([]exportedBinding{existing.module()}, binding.module())
+				delete(starExports, name)
+				continue
+			}
+			starExports[name] = binding
+		}
+	}
+	for name, binding := range starExports {
+		if _, isAmbiguous := ambiguous[name]; !isAmbiguous {
+			exports[name] = binding
+		}
+	}
+
+	return exportTable{bindings: exports, ambiguous: ambiguous}
+}
+
+// resolveExport finds where the name exported by source is declared. When the
+// name can't be found in source, source itself is assumed to declare it.
+func (indexer *moduleIndexer) resolveExport(filePath string, source string, name string) (exportedBinding, bool) {
+	binding, exists := indexer.resolveModule(filePath, source)
+	if !exists {
+		return exportedBinding{}, false
+	}
+	binding.name = name
+	if binding.packageModule != "" {
+		return binding, true
+	}
+
+	sourceExports := indexer.moduleExports(binding.filePath)
+	if sourceBinding, exists := sourceExports.bindings[name]; exists {
+		return sourceBinding, true
+	}
+	if _, isAmbiguous := sourceExports.ambiguous[name]; isAmbiguous {
+		return exportedBinding{}, false
+	}
+	return binding, true
+}
+
+// resolveModule resolves source to a module file, or to a package for bare
+// module specifiers such as `lodash-es`.
+func (indexer *moduleIndexer) resolveModule(filePath string, source string) (exportedBinding, bool) {
+	if !isRelativeModulePath(source) {
+		return exportedBinding{packageModule: source}, true
+	}
+	sourcePath, exists := resolveModulePath(filePath, source, indexer.extensions, indexer.rootDirs(filePath))
+	if !exists {
+		return exportedBinding{}, false
+	}
+	return exportedBinding{filePath: sourcePath}, true
+}
+
+func (indexer *moduleIndexer) module(filePath string) (tokenizer.Module, bool) {
+	if module, exists := indexer.modules[filePath]; exists {
+		return module, true
+	}
+	contents, err := os.ReadFile(filePath)
+	if err != nil {
+		return tokenizer.Module{}, false
+	}
+	module := tokenizer.Parse(string(contents), tokenizer.SupportsJSX(filePath))
+	indexer.modules[filePath] = module
+	return module, true
+}
+
+func getImportedBindings(module tokenizer.Module) map[string]importedBinding {
+	importedBindings := make(map[string]importedBinding)
+	for _, statement := range module.Imports {
+		if statement.Default != "" {
+			importedBindings[statement.Default] = importedBinding{source: statement.Source, name: "default"}
+		}
+		if statement.Namespace != "" {
+			importedBindings[statement.Namespace] = importedBinding{source: statement.Source, name: "*"}
+		}
+		for _, specifier := range statement.Specifiers {
+			importedBindings[specifier.Alias] = importedBinding{source: statement.Source, name: specifier.Name}
+		}
+	}
+	return importedBindings
+}
+
+// resolutionExtensions are the extensions TypeScript tries for a module path without extension, in order
+var resolutionExtensions = []string{".ts", ".tsx", ".d.ts", ".js", ".jsx"}
+
+// jsExtensionSources are the extensions of the files a JavaScript extension
+// points to, as TypeScript resolves `./button.js` to `./button.ts`.
+var jsExtensionSources = map[string][]string{
+	".js":  {".ts", ".tsx", ".d.ts", ".js", ".jsx"},
+	".jsx": {".tsx", ".jsx"},
+	".mjs": {".mts", ".d.mts", ".mjs"},
+	".cjs": {".cts", ".d.cts", ".cjs"},
+}
+
+// resolveModulePath resolves a relative module specifier imported from
+// filePath to the file it points to as TypeScript does, in the directory of
+// filePath then in the other rootDirs.
+func resolveModulePath(filePath string, source string, extensions []string, rootDirs []string) (string, bool) {
+	if !isRelativeModulePath(source) {
+		return "", false
+	}
+
+	for _, path := range resolver.RootDirPaths(filepath.Join(filepath.Dir(filePath), source), rootDirs) {
+		if modulePath, exists := resolveModuleFile(path, extensions); exists {
+			return modulePath, true
+		}
+	}
+	return "", false
+}
+
+// resolveModuleFile resolves a module path to its file: JavaScript extensions
+// point to TypeScript files, then the path is tried as is, with each
+// extension, then as a directory with an index file.
+func resolveModuleFile(path string, extensions []string) (string, bool) {
+	extension := filepath.Ext(path)
+	for _, sourceExtension := range jsExtensionSources[extension] {
+		if sourcePath := strings.TrimSuffix(path, extension) + sourceExtension; isFile(sourcePath) {
+			return sourcePath, true
+		}
+	}
+	// `./button.ts` with allowImportingTsExtensions
+	if isFile(path) {
+		return path, true
+	}
+	extensions = appendUnique(slices.Clone(resolutionExtensions), extensions...)
+	for _, extension := range extensions {
+		if isFile(path + extension) {
+			return path + extension, true
+		}
+	}
+	for _, extension := range extensions {
+		if indexPath := filepath.Join(path, "index"+extension); isFile(indexPath) {
+			return indexPath, true
+		}
+	}
+	return "", false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func isRelativeModulePath(source string) bool {
+	return source == "." || source == ".." || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendUnique[T comparable](values []T, newValues ...T) []T {
+	for _, newValue := range newValues {
+		if !slices.Contains(values, newValue) {
+			values = append(values, newValue)
+		}
+	}
+	return values
+}
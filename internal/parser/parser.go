@@ -3,32 +3,32 @@ package parser
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/nergie/no-barrel-file/internal/ignorer"
 	"github.com/nergie/no-barrel-file/internal/resolver"
-)
-
-var (
-	// export * from './module' || export * as ModuleName from './module' || export type { ModuleName } from './module' || export { ModuleName } from './module'
-	ExportLineWithPathRX = regexp.MustCompile(`(?i)export\s+(\*\s+from|\*\s+as\s+\w+\s+from|type\s+{[^}]+}\s+from|{[^}]+}\s+from)\s+['"]([^'"]+)['"]`)
-	// export default class ModuleName || export class ModuleName || export function ModuleName || export const ModuleName || export let ModuleName || export enum ModuleName || export type ModuleName || export interface ModuleName || export { ModuleName }
-	ExportLineWithModuleRX = regexp.MustCompile(`export\s+(?:default\s+)?(?:class|function|const|let|var|enum|type|interface)\s+([a-zA-Z_$][a-zA-Z0-9_$]*)|\bexport\s+\{[^}]*\b([a-zA-Z_$][a-zA-Z0-9_$]*)\b[^}]*\}`)
+	"github.com/nergie/no-barrel-file/internal/tokenizer"
 )
 
 type Parser struct {
 	ignorer    ignorer.Ignorer
 	rootPath   string
 	extensions []string
+	// rootDirs are merged into one virtual directory when resolving relative modules
+	rootDirs RootDirs
 }
 
-func New(rootPath string, ignorer ignorer.Ignorer, extensions []string) Parser {
+// RootDirs returns the rootDirs of the config the file at filePath belongs to
+type RootDirs func(filePath string) []string
+
+func New(rootPath string, ignorer ignorer.Ignorer, extensions []string, rootDirs RootDirs) Parser {
 	return Parser{
 		ignorer:    ignorer,
 		rootPath:   rootPath,
 		extensions: extensions,
+		rootDirs:   rootDirs,
 	}
 }
 
@@ -48,7 +48,7 @@ func (parser *Parser) BarrelFilePaths() []string {
 		}
 
 		if !info.IsDir() && isIndexFile(path, parser.extensions) {
-			modulePaths := getBarrelModulePaths(path, parser.extensions)
+			modulePaths := getBarrelModulePaths(path, parser.extensions, parser.rootDirs(path))
 			if len(modulePaths) > 0 {
 				barrelFilePaths = append(barrelFilePaths, path)
 			}
@@ -59,47 +59,84 @@ func (parser *Parser) BarrelFilePaths() []string {
 	return barrelFilePaths
 }
 
-func (parser *Parser) BarrelMaps(resolver resolver.Resolver) (map[string]struct{}, map[string]string) {
-	barrelDirsWithModulePaths := parser.getBarrelDirsWithModulePaths()
+// ModuleExport locates a name exported by a barrel: the module declaring it,
+// relative to the barrel directory and without extension, and its name there.
+type ModuleExport struct {
+	ModulePath string
+	Name       string
+	IsDefault  bool
+	// IsNamespace is set for `export * as ns from './module'`, in which case Name is `*`
+	IsNamespace bool
+	// ModuleFilePath is the path of the module file relative to the barrel
+	// directory, such as `button/index.tsx` for the module path `button`
+	ModuleFilePath string
+	// PackageModule is set instead of ModulePath for names re-exported from
+	// packages, such as `export { debounce } from 'lodash-es'`
+	PackageModule string
+}
+
+// AmbiguousExport is a name exported by several modules of a barrel through
+// `export *`, which TypeScript excludes from the barrel exports.
+type AmbiguousExport struct {
+	BarrelPath  string
+	Name        string
+	ModulePaths []string
+}
+
+// BarrelMaps indexes barrel files by directory: the existence of barrels, the
+// module exporting each name of a barrel and the names exported ambiguously.
+func (parser *Parser) BarrelMaps() (map[string]struct{}, map[string]ModuleExport, map[string][]string) {
 	barrelPathExistenceMap := make(map[string]struct{})
-	barrelModuleResolverMap := make(map[string]string)
-	for barrelDir, modulePaths := range barrelDirsWithModulePaths {
-		barrelDirAlias := resolver.AliasPath(barrelDir)
-		for _, modulePath := range modulePaths {
-			moduleRelativePath := filepath.Join(barrelDir, modulePath)
-			filepath.Walk(moduleRelativePath, func(path string, info os.FileInfo, err error) error {
-				if err != nil || info.IsDir() || !parser.IsSupportedFileExtension(path) {
-					return nil
-				}
-
-				contents, err := os.ReadFile(path)
-				if err != nil {
-					return nil
-				}
-
-				matches := ExportLineWithModuleRX.FindAllStringSubmatch(string(contents), -1)
-				for _, match := range matches {
-					if len(match) > 1 {
-						barrelPathExistenceMap[barrelDirAlias.FullPath] = struct{}{}
-						barrelPathExistenceMap[barrelDir] = struct{}{}
-
-						moduleName := match[1]
-						aliasKey := filepath.Join(barrelDirAlias.FullPath, moduleName)
-						directKey := filepath.Join(barrelDir, moduleName)
-						moduleExtension := filepath.Ext(modulePath)
-						modulePathWithoutExtension := modulePath[0 : len(modulePath)-len(moduleExtension)]
-						aliasValue := filepath.Join(modulePathWithoutExtension)
-						directValue := filepath.Join(modulePathWithoutExtension)
-						barrelModuleResolverMap[aliasKey] = aliasValue
-						barrelModuleResolverMap[directKey] = directValue
-					}
-				}
-				return nil
-			})
+	barrelModuleResolverMap := make(map[string]ModuleExport)
+	barrelAmbiguousExportMap := make(map[string][]string)
+	indexer := newModuleIndexer(parser.extensions, parser.rootDirs)
+	for _, barrelPath := range parser.BarrelFilePaths() {
+		barrelDir := filepath.ToSlash(filepath.Dir(barrelPath))
+		rootDirs := parser.rootDirs(barrelPath)
+		exports := indexer.moduleExports(barrelPath)
+		for exportName, binding := range exports.bindings {
+			barrelPathExistenceMap[barrelDir] = struct{}{}
+			if binding.filePath == barrelPath {
+				continue
+			}
+
+			moduleExport := ModuleExport{
+				Name:          binding.name,
+				IsDefault:     binding.name == "default",
+				IsNamespace:   binding.name == "*",
+				PackageModule: binding.packageModule,
+			}
+			if binding.packageModule == "" {
+				moduleExport.ModulePath = getModulePath(barrelDir, binding.filePath, rootDirs)
+				moduleExport.ModuleFilePath = getModuleFilePath(barrelDir, binding.filePath, rootDirs)
+			}
+			barrelModuleResolverMap[filepath.Join(barrelDir, exportName)] = moduleExport
+		}
+		for exportName, modules := range exports.ambiguous {
+			modulePaths := getBindingModulePaths(barrelDir, modules, rootDirs)
+			barrelAmbiguousExportMap[filepath.Join(barrelDir, exportName)] = modulePaths
 		}
 	}
 
-	return barrelPathExistenceMap, barrelModuleResolverMap
+	return barrelPathExistenceMap, barrelModuleResolverMap, barrelAmbiguousExportMap
+}
+
+func (parser *Parser) AmbiguousExports() []AmbiguousExport {
+	ambiguousExports := []AmbiguousExport{}
+	indexer := newModuleIndexer(parser.extensions, parser.rootDirs)
+	for _, barrelPath := range parser.BarrelFilePaths() {
+		barrelDir := filepath.Dir(barrelPath)
+		ambiguous := indexer.moduleExports(barrelPath).ambiguous
+		for _, exportName := range sortedKeys(ambiguous) {
+			modulePaths := getBindingModulePaths(barrelDir, ambiguous[exportName], parser.rootDirs(barrelPath))
+			ambiguousExports = append(ambiguousExports, AmbiguousExport{
+				BarrelPath:  barrelPath,
+				Name:        exportName,
+				ModulePaths: modulePaths,
+			})
+		}
+	}
+	return ambiguousExports
 }
 
 func (parser *Parser) IsSupportedFileExtension(path string) bool {
@@ -112,35 +149,7 @@ func (parser *Parser) IsSupportedFileExtension(path string) bool {
 	return false
 }
 
-func (parser *Parser) getBarrelDirsWithModulePaths() map[string][]string {
-	barrelDirsWithModulePaths := make(map[string][]string)
-	filepath.Walk(parser.rootPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Unable to open file %s: %v\n", path, err)
-			return nil
-		}
-
-		if parser.ignorer.IgnorePath(path) {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		if !info.IsDir() && isIndexFile(path, parser.extensions) {
-			modulePaths := getBarrelModulePaths(path, parser.extensions)
-			if len(modulePaths) > 0 {
-				dirPath := filepath.ToSlash(filepath.Dir(path))
-				barrelDirsWithModulePaths[dirPath] = modulePaths
-			}
-		}
-		return nil
-	})
-	handleNestedBarrels(&barrelDirsWithModulePaths)
-	return barrelDirsWithModulePaths
-}
-
-func getBarrelModulePaths(filePath string, extensions []string) []string {
+func getBarrelModulePaths(filePath string, extensions []string, rootDirs []string) []string {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error opening barrel file %s: %v\n", filePath, err)
@@ -148,77 +157,72 @@ func getBarrelModulePaths(filePath string, extensions []string) []string {
 	}
 
 	var modulePaths []string
-	matches := ExportLineWithPathRX.FindAllStringSubmatch(string(content), -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			modulePath := match[2]
-			path := filepath.Join(filepath.Dir(filePath), modulePath)
-			if info, err := os.Stat(path); err == nil {
-				if info.IsDir() {
-					modulePaths = append(modulePaths, filepath.ToSlash(modulePath))
-				}
-			} else {
-				for _, extension := range extensions {
-					pathWithExtension := path + extension
-					if _, err := os.Stat(pathWithExtension); err == nil {
-						modulePaths = append(modulePaths, filepath.ToSlash(modulePath+extension))
-						break
-					}
-				}
-			}
+	module := tokenizer.Parse(string(content), tokenizer.SupportsJSX(filePath))
+	for _, export := range module.Exports {
+		if export.Source == "" {
+			continue
+		}
+		if modulePath, exists := resolveModulePath(filePath, export.Source, extensions, rootDirs); exists {
+			modulePaths = append(modulePaths, filepath.ToSlash(modulePath))
 		}
 	}
 
 	return modulePaths
 }
 
-func isIndexFile(path string, extensions []string) bool {
-	for _, ext := range extensions {
-		if filepath.Base(path) == "index"+ext {
-			return true
+// getBindingModulePaths returns the module paths of bindings relative to a
+// barrel directory, or their package module specifiers
+func getBindingModulePaths(barrelDir string, bindings []exportedBinding, rootDirs []string) []string {
+	modulePaths := []string{}
+	for _, binding := range bindings {
+		if binding.packageModule != "" {
+			modulePaths = append(modulePaths, binding.packageModule)
+		} else {
+			modulePaths = append(modulePaths, getModulePath(barrelDir, binding.filePath, rootDirs))
 		}
 	}
-	return false
+	return modulePaths
 }
 
-func handleNestedBarrels(barrelDirsWithModulePaths *map[string][]string) {
-	for dir, modulePaths := range *barrelDirsWithModulePaths {
-		visitedDirs := map[string]struct{}{}
-		visitedDirs[dir] = struct{}{}
-		resolvedModulePaths := []string{}
-		for _, modulePath := range modulePaths {
-			path := filepath.Join(dir, modulePath)
-			if _, exists := (*barrelDirsWithModulePaths)[path]; exists {
-				resolvedNestedModulePaths := getResolvedModulePaths(path, modulePath, *barrelDirsWithModulePaths, visitedDirs)
-				resolvedModulePaths = append(resolvedModulePaths, resolvedNestedModulePaths...)
-			} else {
-				resolvedModulePaths = append(resolvedModulePaths, modulePath)
-			}
-		}
-		(*barrelDirsWithModulePaths)[dir] = resolvedModulePaths
+// getModulePath returns the path of a module relative to a barrel directory, without extension
+func getModulePath(barrelDir string, filePath string, rootDirs []string) string {
+	modulePath := getModuleFilePath(barrelDir, filePath, rootDirs)
+	modulePath = strings.TrimSuffix(modulePath, ModuleExtension(modulePath))
+	if path.Base(modulePath) == "index" {
+		modulePath = path.Dir(modulePath)
 	}
+	return modulePath
 }
 
-func getResolvedModulePaths(fullDirPath string, relDirPath string, barrelDirsWithModulePaths map[string][]string, visitedDirs map[string]struct{}) []string {
-	if _, exists := visitedDirs[fullDirPath]; exists {
-		return []string{}
+// getModuleFilePath returns the path of a module file relative to a barrel
+// directory, within the virtual directory of rootDirs.
+func getModuleFilePath(barrelDir string, filePath string, rootDirs []string) string {
+	filePath = resolver.VirtualPath(filePath, filepath.FromSlash(barrelDir), rootDirs)
+	modulePath, err := filepath.Rel(barrelDir, filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
 	}
-	nestedPaths, exists := barrelDirsWithModulePaths[fullDirPath]
-	if !exists {
-		return []string{}
+	return filepath.ToSlash(modulePath)
+}
+
+// declarationExtensions are the extensions of TypeScript declaration files
+var declarationExtensions = []string{".d.ts", ".d.mts", ".d.cts"}
+
+// ModuleExtension returns the extension of a module file, such as `.d.ts` for `types.d.ts`
+func ModuleExtension(filePath string) string {
+	for _, extension := range declarationExtensions {
+		if strings.HasSuffix(filePath, extension) {
+			return extension
+		}
 	}
+	return filepath.Ext(filePath)
+}
 
-	visitedDirs[fullDirPath] = struct{}{}
-	resolvedModulePaths := []string{}
-	for _, modulePath := range nestedPaths {
-		path := filepath.Join(fullDirPath, modulePath)
-		resolvedModulePath := filepath.Join(relDirPath, modulePath)
-		if _, exists := barrelDirsWithModulePaths[path]; exists {
-			resolvedNestedModulePaths := getResolvedModulePaths(path, resolvedModulePath, barrelDirsWithModulePaths, visitedDirs)
-			resolvedModulePaths = append(resolvedModulePaths, resolvedNestedModulePaths...)
-		} else {
-			resolvedModulePaths = append(resolvedModulePaths, resolvedModulePath)
+func isIndexFile(path string, extensions []string) bool {
+	for _, ext := range extensions {
+		if filepath.Base(path) == "index"+ext {
+			return true
 		}
 	}
-	return resolvedModulePaths
+	return false
 }
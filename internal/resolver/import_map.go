@@ -0,0 +1,140 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+// ImportMap is an import map file, or a deno.json declaring an import map
+// either inline or through the path of its import map file.
+type ImportMap struct {
+	Imports   map[string]string            `json:"imports"`
+	Scopes    map[string]map[string]string `json:"scopes"`
+	ImportMap string                       `json:"importMap"`
+}
+
+// importMap is the aliases of an import map such as `"@ui/": "./src/ui/"`,
+// the aliases of its scopes applying to the modules within them.
+type importMap struct {
+	imports []aliasPattern
+	scopes  []importMapScope // most specific first
+}
+
+type importMapScope struct {
+	path     string
+	isPrefix bool // `"./vendor/"` scopes a directory rather than a single module
+	imports  []aliasPattern
+}
+
+// denoConfigNames are the configs auto-detected at the root path for their import map
+var denoConfigNames = []string{"deno.json", "deno.jsonc"}
+
+func getImportMap(rootPath string, importMapPath *string) (importMap, error) {
+	if importMapPath != nil && *importMapPath != "" {
+		return loadImportMap(filepath.Join(rootPath, *importMapPath))
+	}
+	for _, configName := range denoConfigNames {
+		configPath := filepath.Join(rootPath, configName)
+		if info, err := os.Stat(configPath); err == nil && !info.IsDir() {
+			return loadImportMap(configPath)
+		}
+	}
+	return importMap{}, nil
+}
+
+func loadImportMap(importMapPath string) (importMap, error) {
+	file, err := readImportMap(importMapPath)
+	if err == nil && file.Imports == nil && file.Scopes == nil && file.ImportMap != "" {
+		// "importMap": "./import_map.json" in deno.json
+		importMapPath = filepath.Join(filepath.Dir(importMapPath), file.ImportMap)
+		file, err = readImportMap(importMapPath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing import map: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Ignoring import map file\n")
+		return importMap{}, fmt.Errorf("unable to load the import map %s: %w", importMapPath, err)
+	}
+
+	importMapDir := filepath.Dir(importMapPath)
+	result := importMap{imports: parseImportMapAliases(file.Imports, importMapDir)}
+	for scopeKey, imports := range file.Scopes {
+		result.scopes = append(result.scopes, importMapScope{
+			path:     filepath.Join(importMapDir, scopeKey),
+			isPrefix: strings.HasSuffix(scopeKey, "/"),
+			imports:  parseImportMapAliases(imports, importMapDir),
+		})
+	}
+	// A module matches a single exact scope, which is more specific than any
+	// prefix scope containing it
+	sort.Slice(result.scopes, func(i, j int) bool {
+		if result.scopes[i].isPrefix != result.scopes[j].isPrefix {
+			return !result.scopes[i].isPrefix
+		}
+		if len(result.scopes[i].path) != len(result.scopes[j].path) {
+			return len(result.scopes[i].path) > len(result.scopes[j].path)
+		}
+		return result.scopes[i].path < result.scopes[j].path
+	})
+	return result, nil
+}
+
+func readImportMap(importMapPath string) (ImportMap, error) {
+	file, err := os.ReadFile(importMapPath)
+	if err != nil {
+		return ImportMap{}, err
+	}
+	b, err := hujson.Standardize(file)
+	if err != nil {
+		return ImportMap{}, err
+	}
+	var importMap ImportMap
+	if err := json.Unmarshal(b, &importMap); err != nil {
+		return ImportMap{}, err
+	}
+	return importMap, nil
+}
+
+// parseImportMapAliases returns the aliases of the `imports` of an import map.
+// Keys ending with `/` map every import path they prefix, as `paths` wildcards do.
+func parseImportMapAliases(imports map[string]string, importMapDir string) []aliasPattern {
+	aliases := []aliasPattern{}
+	for key, target := range imports {
+		// Only targets within the project are aliases, `"std/": "https://deno.land/std/"` imports a package
+		if !strings.HasPrefix(target, "./") && !strings.HasPrefix(target, "../") {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(key, "/") && strings.HasSuffix(target, "/"):
+			aliases = append(aliases, newAliasPattern(key+"*", []string{target + "*"}, importMapDir))
+		case !strings.HasSuffix(key, "/"):
+			aliases = append(aliases, newAliasPattern(key, []string{target}, importMapDir))
+		}
+	}
+	sortAliases(aliases)
+	return aliases
+}
+
+// importMapAliases returns the import map aliases of the file at filePath: the
+// ones of the scopes containing it, most specific first, then the top-level ones.
+func (resolver *Resolver) importMapAliases(filePath string) []aliasPattern {
+	aliases := []aliasPattern{}
+	for _, scope := range resolver.importMap.scopes {
+		if scope.contains(filePath) {
+			aliases = append(aliases, scope.imports...)
+		}
+	}
+	return append(aliases, resolver.importMap.imports...)
+}
+
+func (scope importMapScope) contains(filePath string) bool {
+	if !scope.isPrefix {
+		return filePath == scope.path
+	}
+	return strings.HasPrefix(filePath, scope.path+string(filepath.Separator))
+}
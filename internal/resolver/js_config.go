@@ -0,0 +1,289 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nergie/no-barrel-file/internal/tokenizer"
+)
+
+type jsValueKind int
+
+const (
+	jsUnknown jsValueKind = iota
+	jsString
+	jsPath // a path built from the config directory, such as path.resolve(__dirname, 'src')
+	jsObject
+	jsArray
+)
+
+// jsValue is a value of a JavaScript or JSON config which can be evaluated
+// statically: a string, a path, or an object or array literal of such values.
+type jsValue struct {
+	kind       jsValueKind
+	text       string
+	properties []jsProperty // properties of an object literal, in order
+	elements   []jsValue
+}
+
+type jsProperty struct {
+	key   string
+	value jsValue
+}
+
+// jsConfigParser evaluates the static values of a config, relative to the
+// directory of the config for `__dirname` and the like.
+type jsConfigParser struct {
+	tokens    []tokenizer.Token
+	index     int
+	configDir string
+}
+
+// readJSConfigProperty returns the first object or array literal assigned to
+// property in the config at configPath, such as the `alias` of
+// `resolve: { alias: { '@ui': './src/ui' } }`. JSON configs are parsed as
+// JavaScript object literals.
+func readJSConfigProperty(configPath string, property string) (jsValue, bool) {
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		return jsValue{}, false
+	}
+
+	p := jsConfigParser{tokens: tokenizer.Tokenize(string(contents), false), configDir: filepath.Dir(configPath)}
+	for i, token := range p.tokens {
+		if token.Kind != tokenizer.Identifier && token.Kind != tokenizer.String {
+			continue
+		}
+		if tokenizer.StringValue(token) != property || i+1 >= len(p.tokens) || p.tokens[i+1].Value != ":" {
+			continue
+		}
+		p.index = i + 2
+		if value := p.parseValue(); value.kind == jsObject || value.kind == jsArray {
+			return value, true
+		}
+	}
+	return jsValue{}, false
+}
+
+// parseValue parses an expression, which is unknown unless it is static
+func (p *jsConfigParser) parseValue() jsValue {
+	value := p.parseOperand()
+	// __dirname + '/src'
+	for value.kind != jsUnknown && p.isValue(0, "+") {
+		p.index++
+		operand := p.parseOperand()
+		if operand.kind != jsString || (value.kind != jsString && value.kind != jsPath) {
+			value = jsValue{}
+			break
+		}
+		value.text += operand.text
+	}
+	// { ... } as const
+	if value.kind != jsUnknown && (p.isValue(0, "as") || p.isValue(0, "satisfies")) {
+		p.skipValue()
+	}
+	if value.kind == jsUnknown || !p.isValueEnd() {
+		p.skipValue()
+		return jsValue{}
+	}
+	return value
+}
+
+func (p *jsConfigParser) parseOperand() jsValue {
+	switch {
+	case p.isKind(0, tokenizer.String):
+		p.index++
+		return jsValue{kind: jsString, text: tokenizer.StringValue(p.tokens[p.index-1])}
+	case p.isKind(0, tokenizer.Template):
+		return p.parseTemplate()
+	case p.isValue(0, "{"):
+		return p.parseObject()
+	case p.isValue(0, "["):
+		return p.parseArray()
+	case p.isValue(0, "__dirname"):
+		p.index++
+		return jsValue{kind: jsPath, text: p.configDir}
+	case p.isValue(0, "import") && p.isValue(1, ".") && p.isValue(2, "meta") && p.isValue(3, ".") && p.isValue(4, "dirname"):
+		p.index += 5
+		return jsValue{kind: jsPath, text: p.configDir}
+	case p.isValue(0, "process") && p.isValue(1, ".") && p.isValue(2, "cwd") && p.isValue(3, "(") && p.isValue(4, ")"):
+		p.index += 5
+		return jsValue{kind: jsPath, text: p.configDir}
+	case p.isValue(0, "path") && p.isValue(1, ".") && (p.isValue(2, "resolve") || p.isValue(2, "join")) && p.isValue(3, "("):
+		p.index += 3
+		return p.parsePathCall()
+	case (p.isValue(0, "resolve") || p.isValue(0, "join")) && p.isValue(1, "("):
+		p.index++
+		return p.parsePathCall()
+	case p.isValue(0, "fileURLToPath") && p.isValue(1, "("):
+		// fileURLToPath(new URL('./src', import.meta.url))
+		p.index += 2
+		value := p.parseURL()
+		if value.kind == jsUnknown || !p.isValue(0, ")") {
+			return jsValue{}
+		}
+		p.index++
+		return value
+	case p.isValue(0, "new"):
+		// new URL('./src', import.meta.url).pathname
+		value := p.parseURL()
+		if value.kind == jsUnknown || !p.isValue(0, ".") || !p.isValue(1, "pathname") {
+			return jsValue{}
+		}
+		p.index += 2
+		return value
+	}
+	return jsValue{}
+}
+
+// parseTemplate parses a template literal whose substitutions are static, such as `${__dirname}/src`
+func (p *jsConfigParser) parseTemplate() jsValue {
+	value := jsValue{kind: jsString}
+	for p.isKind(0, tokenizer.Template) {
+		text := p.tokens[p.index].Value
+		p.index++
+		if strings.HasPrefix(text, "`") {
+			text = text[1:]
+		} else {
+			text = strings.TrimPrefix(text, "}")
+		}
+		if !strings.HasSuffix(text, "${") {
+			value.text += strings.TrimSuffix(text, "`")
+			return value
+		}
+		value.text += strings.TrimSuffix(text, "${")
+
+		substitution := p.parseOperand()
+		switch {
+		case substitution.kind == jsPath && value.text == "":
+			value.kind = jsPath
+		case substitution.kind != jsString:
+			return jsValue{}
+		}
+		value.text += substitution.text
+	}
+	return jsValue{}
+}
+
+func (p *jsConfigParser) parseObject() jsValue {
+	value := jsValue{kind: jsObject}
+	p.index++
+	for p.index < len(p.tokens) && !p.isValue(0, "}") {
+		start := p.index
+		switch {
+		case p.isValue(0, ","):
+			p.index++
+			continue
+		case (p.isKind(0, tokenizer.Identifier) || p.isKind(0, tokenizer.String) || p.isKind(0, tokenizer.Number)) && p.isValue(1, ":"):
+			key := tokenizer.StringValue(p.tokens[p.index])
+			p.index += 2
+			value.properties = append(value.properties, jsProperty{key: key, value: p.parseValue()})
+		default:
+			// Spreads, methods, shorthand and computed properties are not static
+			p.skipValue()
+		}
+		if p.index == start {
+			// Unexpected token, skip it to make progress
+			p.index++
+		}
+	}
+	p.index = min(p.index+1, len(p.tokens))
+	return value
+}
+
+func (p *jsConfigParser) parseArray() jsValue {
+	value := jsValue{kind: jsArray}
+	p.index++
+	for p.index < len(p.tokens) && !p.isValue(0, "]") {
+		start := p.index
+		if p.isValue(0, ",") {
+			p.index++
+			continue
+		}
+		value.elements = append(value.elements, p.parseValue())
+		if p.index == start {
+			p.index++
+		}
+	}
+	p.index = min(p.index+1, len(p.tokens))
+	return value
+}
+
+// parsePathCall parses the arguments of path.resolve() and path.join(), the
+// current token being `(`.
+func (p *jsConfigParser) parsePathCall() jsValue {
+	p.index++
+	value := jsValue{kind: jsString}
+	for p.index < len(p.tokens) && !p.isValue(0, ")") {
+		if p.isValue(0, ",") {
+			p.index++
+			continue
+		}
+		argument := p.parseValue()
+		switch {
+		case argument.kind == jsPath || (argument.kind == jsString && filepath.IsAbs(argument.text)):
+			value = jsValue{kind: jsPath, text: argument.text}
+		case argument.kind == jsString:
+			value.text = filepath.Join(value.text, argument.text)
+		default:
+			return jsValue{}
+		}
+	}
+	if !p.isValue(0, ")") {
+		return jsValue{}
+	}
+	p.index++
+	if value.kind == jsString {
+		// path.resolve('src') is relative to the working directory
+		value = jsValue{kind: jsPath, text: filepath.Join(p.configDir, value.text)}
+	}
+	return value
+}
+
+// parseURL parses `new URL('./src', import.meta.url)` as a path
+func (p *jsConfigParser) parseURL() jsValue {
+	if !p.isValue(0, "new") || !p.isValue(1, "URL") || !p.isValue(2, "(") || !p.isKind(3, tokenizer.String) || !p.isValue(4, ",") {
+		return jsValue{}
+	}
+	url := tokenizer.StringValue(p.tokens[p.index+3])
+	p.index += 5
+	if !p.isValue(0, "import") || !p.isValue(1, ".") || !p.isValue(2, "meta") || !p.isValue(3, ".") || !p.isValue(4, "url") || !p.isValue(5, ")") {
+		return jsValue{}
+	}
+	p.index += 6
+	return jsValue{kind: jsPath, text: filepath.Join(p.configDir, url)}
+}
+
+// skipValue skips the tokens of an expression up to the next `,` or closing bracket
+func (p *jsConfigParser) skipValue() {
+	depth := 0
+	for ; p.index < len(p.tokens); p.index++ {
+		switch {
+		case p.isValue(0, "(") || p.isValue(0, "[") || p.isValue(0, "{"):
+			depth++
+		case p.isValue(0, ")") || p.isValue(0, "]") || p.isValue(0, "}"):
+			if depth == 0 {
+				return
+			}
+			depth--
+		case p.isValue(0, ",") && depth == 0:
+			return
+		}
+	}
+}
+
+// isValueEnd reports whether the current token ends a value
+func (p *jsConfigParser) isValueEnd() bool {
+	return p.index >= len(p.tokens) || p.isValue(0, ",") || p.isValue(0, ")") || p.isValue(0, "]") || p.isValue(0, "}") || p.isValue(0, ";") || p.isKind(0, tokenizer.Template)
+}
+
+func (p *jsConfigParser) isKind(n int, kind tokenizer.Kind) bool {
+	index := p.index + n
+	return index >= 0 && index < len(p.tokens) && p.tokens[index].Kind == kind
+}
+
+func (p *jsConfigParser) isValue(n int, value string) bool {
+	index := p.index + n
+	return index >= 0 && index < len(p.tokens) && p.tokens[index].Kind != tokenizer.String && p.tokens[index].Value == value
+}
@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// subpathConditions are the conditions of conditional subpath imports and
+// exports resolved to source files, tried in the order of the package.json.
+var subpathConditions = []string{"source", "types", "import", "require", "node", "module", "default"}
+
+// subpathImports returns the subpath imports of the package.json closest to
+// the file at filePath, such as `"#ui/*": "./src/ui/*.js"`.
+func (resolver *Resolver) subpathImports(filePath string) []aliasPattern {
+	return resolver.dirSubpathImports(filepath.Dir(filePath))
+}
+
+func (resolver *Resolver) dirSubpathImports(dir string) []aliasPattern {
+	if aliases, exists := resolver.packageImports[dir]; exists {
+		return aliases
+	}
+
+	var aliases []aliasPattern
+	file, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	switch {
+	case err == nil:
+		aliases = parseSubpathImports(file, dir)
+	case filepath.Dir(dir) != dir:
+		aliases = resolver.dirSubpathImports(filepath.Dir(dir))
+	}
+	resolver.packageImports[dir] = aliases
+	return aliases
+}
+
+func parseSubpathImports(file []byte, packageDir string) []aliasPattern {
+	var packageJSON struct {
+		Imports map[string]json.RawMessage `json:"imports"`
+	}
+	if err := json.Unmarshal(file, &packageJSON); err != nil {
+		return nil
+	}
+
+	aliases := []aliasPattern{}
+	for key, target := range packageJSON.Imports {
+		if !strings.HasPrefix(key, "#") {
+			continue
+		}
+		// Only targets within the package are aliases, `"#dep": "dep"` imports a package
+		targets := slices.DeleteFunc(getSubpathTargets(target), func(target string) bool {
+			return !strings.HasPrefix(target, "./")
+		})
+		if len(targets) > 0 {
+			aliases = append(aliases, newAliasPattern(key, targets, packageDir))
+		}
+	}
+	sortAliases(aliases)
+	return aliases
+}
+
+// getSubpathTargets flattens a subpath import target, which is either a path,
+// an array of fallback targets or an object of conditional targets.
+func getSubpathTargets(target json.RawMessage) []string {
+	var path string
+	if err := json.Unmarshal(target, &path); err == nil {
+		return []string{path}
+	}
+	var fallbacks []json.RawMessage
+	if err := json.Unmarshal(target, &fallbacks); err == nil {
+		targets := []string{}
+		for _, fallback := range fallbacks {
+			targets = append(targets, getSubpathTargets(fallback)...)
+		}
+		return targets
+	}
+
+	// Conditions are ordered, so the object is decoded key by key
+	decoder := json.NewDecoder(bytes.NewReader(target))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	targets := []string{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		var conditionalTarget json.RawMessage
+		if err := decoder.Decode(&conditionalTarget); err != nil {
+			break
+		}
+		if condition, ok := token.(string); ok && slices.Contains(subpathConditions, condition) {
+			targets = append(targets, getSubpathTargets(conditionalTarget)...)
+		}
+	}
+	return targets
+}
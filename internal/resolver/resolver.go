@@ -1,94 +1,373 @@
 package resolver
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-
-	"github.com/tailscale/hujson"
 )
 
 type Resolver struct {
-	aliasPaths map[string]string
-	rootPath   string
+	config    aliasConfig
+	importMap importMap
+	// workspaces are the packages of the monorepo at the root path
+	workspaces workspaces
+	// toolAliases are the aliases of the Babel, Jest, Vite and webpack configs
+	toolAliases []aliasPattern
+	rootPath    string
+	// packageImports caches the subpath imports of the closest package.json per directory
+	packageImports map[string][]aliasPattern
+	// findNearestConfig resolves the aliases of each file with the closest
+	// tsconfig.json or jsconfig.json, cached per directory in nearestConfigs
+	findNearestConfig bool
+	nearestConfigs    map[string]aliasConfig
+	// loadErrors are the errors of the configs ignored as they failed to load,
+	// shared by the copies of the resolver as nearest configs load lazily
+	loadErrors *[]error
 }
 
-func New(rootPath string, tsConfigPath *string) Resolver {
+// New returns the resolver of the aliases of the tsconfig at tsConfigPath, of
+// the import map at importMapPath, defaulting to the one of a deno.json at the
+// root path, of the workspace packages and of the tool configs at the root path.
+func New(rootPath string, tsConfigPath *string, importMapPath *string, findNearestConfig bool) Resolver {
+	loadErrors := []error{}
+	config, err := getAliasConfig(rootPath, tsConfigPath)
+	if err != nil {
+		loadErrors = append(loadErrors, err)
+	}
+	importMap, err := getImportMap(rootPath, importMapPath)
+	if err != nil {
+		loadErrors = append(loadErrors, err)
+	}
+	workspaces, workspaceErrors := getWorkspaces(rootPath)
+	loadErrors = append(loadErrors, workspaceErrors...)
 	return Resolver{
-		aliasPaths: getAliasPaths(rootPath, tsConfigPath),
-		rootPath:   rootPath,
+		config:            config,
+		importMap:         importMap,
+		workspaces:        workspaces,
+		toolAliases:       getToolAliases(rootPath),
+		rootPath:          rootPath,
+		findNearestConfig: findNearestConfig,
+		nearestConfigs:    make(map[string]aliasConfig),
+		packageImports:    make(map[string][]aliasPattern),
+		loadErrors:        &loadErrors,
 	}
 }
 
-type TSConfig struct {
-	CompilerOptions struct {
-		Paths   map[string][]string `json:"paths"`
-		BaseUrl string              `json:"baseUrl"`
-	} `json:"compilerOptions"`
+// LoadErrors returns the errors of the tsconfig, import map and workspace
+// configs which failed to load and were ignored. Nearest configs only load
+// once the files they apply to are resolved.
+func (resolver *Resolver) LoadErrors() []error {
+	return *resolver.loadErrors
 }
 
-func getAliasPaths(rootPath string, tsConfigPath *string) map[string]string {
-	aliasPaths := make(map[string]string)
+// aliasConfig is how a tsconfig resolves non-relative import paths: through
+// its `paths` aliases, then relative to its baseUrl. Relative import paths may
+// also point to any of its rootDirs, merged into one virtual directory.
+type aliasConfig struct {
+	aliases  []aliasPattern
+	baseUrl  string
+	rootDirs []string
+}
+
+// aliasPattern is an entry of tsconfig `paths` such as `"@ui/*": ["./src/ui/*"]`
+// or of package.json `imports` such as `"#ui/*": "./src/ui/*.js"`, with a key
+// matching import paths either exactly or through a `*` wildcard. Import map
+// prefixes such as `"@ui/": "./src/ui/"` are wildcards too.
+type aliasPattern struct {
+	key         string
+	prefix      string // part of the key before `*`
+	suffix      string // part of the key after `*`
+	hasWildcard bool
+	// targets are absolute path patterns, tried in order
+	targets []string
+}
+
+// moduleExtensions are the extensions tried when checking whether an alias
+// target exists, declaration extensions first so that they are trimmed whole.
+var moduleExtensions = []string{".d.ts", ".d.mts", ".d.cts", ".ts", ".tsx", ".js", ".jsx", ".mts", ".cts", ".mjs", ".cjs"}
+
+func getAliasConfig(rootPath string, tsConfigPath *string) (aliasConfig, error) {
 	if tsConfigPath == nil || *tsConfigPath == "" {
-		return aliasPaths
-	}
-	tsConfigFullPath := filepath.Join(rootPath, *tsConfigPath)
-	file, err := os.ReadFile(tsConfigFullPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error opening tsconfig file: %v\n", err)
-		return nil
+		return aliasConfig{}, nil
 	}
+	return loadAliasConfig(filepath.Join(rootPath, *tsConfigPath))
+}
 
-	b, err := hujson.Standardize(file)
+func loadAliasConfig(tsConfigFullPath string) (aliasConfig, error) {
+	options, err := loadCompilerOptions(tsConfigFullPath, make(map[string]struct{}))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing tsconfig: %v\n", err)
 		fmt.Fprintf(os.Stderr, "Ignoring tsconfig file\n")
+		return aliasConfig{}, fmt.Errorf("unable to load the tsconfig %s: %w", tsConfigFullPath, err)
 	}
 
-	var tsConfig TSConfig
-	if err := json.Unmarshal(b, &tsConfig); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing tsconfig: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Ignoring tsconfig file\n")
-		return nil
+	aliases := []aliasPattern{}
+	baseUrlPath := options.pathsBaseUrl()
+	for key, targets := range options.paths {
+		aliases = append(aliases, newAliasPattern(key, targets, baseUrlPath))
+	}
+
+	sortAliases(aliases)
+	return aliasConfig{aliases: aliases, baseUrl: options.baseUrl, rootDirs: options.rootDirs}, nil
+}
+
+// newAliasPattern returns the alias of key, with targets relative to basePath
+func newAliasPattern(key string, targets []string, basePath string) aliasPattern {
+	alias := aliasPattern{key: key, prefix: key}
+	if wildcardIndex := strings.Index(key, "*"); wildcardIndex >= 0 {
+		alias.hasWildcard = true
+		alias.prefix, alias.suffix = key[:wildcardIndex], key[wildcardIndex+1:]
+	}
+	for _, target := range targets {
+		alias.targets = append(alias.targets, filepath.Join(basePath, target))
+	}
+	return alias
+}
+
+// sortAliases sorts aliases by precedence: as TypeScript and Node.js do, exact
+// keys take precedence over patterns, then the pattern with the longest prefix wins.
+func sortAliases(aliases []aliasPattern) {
+	sort.Slice(aliases, func(i, j int) bool {
+		if aliases[i].hasWildcard != aliases[j].hasWildcard {
+			return !aliases[i].hasWildcard
+		}
+		if len(aliases[i].prefix) != len(aliases[j].prefix) {
+			return len(aliases[i].prefix) > len(aliases[j].prefix)
+		}
+		return aliases[i].key < aliases[j].key
+	})
+}
+
+// match returns the part of importPath matched by the wildcard of the alias
+func (alias aliasPattern) match(importPath string) (string, bool) {
+	if !alias.hasWildcard {
+		return "", importPath == alias.key
 	}
+	if len(importPath) < len(alias.prefix)+len(alias.suffix) || !strings.HasPrefix(importPath, alias.prefix) || !strings.HasSuffix(importPath, alias.suffix) {
+		return "", false
+	}
+	return importPath[len(alias.prefix) : len(importPath)-len(alias.suffix)], true
+}
+
+// fileConfig returns the alias config of the file at filePath
+func (resolver *Resolver) fileConfig(filePath string) aliasConfig {
+	if !resolver.findNearestConfig {
+		return resolver.config
+	}
+	return resolver.dirConfig(filepath.Dir(filePath))
+}
 
-	if tsConfig.CompilerOptions.BaseUrl == "" {
-		tsConfig.CompilerOptions.BaseUrl = filepath.Dir(tsConfigFullPath)
+// dirConfig returns the alias config of the closest config of dir within the
+// root path, defaulting to the alias config path.
+func (resolver *Resolver) dirConfig(dir string) aliasConfig {
+	if config, exists := resolver.nearestConfigs[dir]; exists {
+		return config
 	}
-	baseUrlPath := filepath.Dir(filepath.Join(tsConfigFullPath, tsConfig.CompilerOptions.BaseUrl))
 
-	for alias, paths := range tsConfig.CompilerOptions.Paths {
-		if len(paths) > 0 {
-			for _, path := range paths {
-				realPath := filepath.Join(baseUrlPath, filepath.Dir(path))
-				aliasPaths[realPath] = strings.TrimSuffix(alias, "/*")
+	config := resolver.config
+	configPath, exists := findConfig(dir)
+	switch {
+	case exists:
+		var err error
+		if config, err = loadAliasConfig(configPath); err != nil {
+			*resolver.loadErrors = append(*resolver.loadErrors, err)
+		}
+	case dir != filepath.Clean(resolver.rootPath) && filepath.Dir(dir) != dir:
+		config = resolver.dirConfig(filepath.Dir(dir))
+	}
+	resolver.nearestConfigs[dir] = config
+	return config
+}
+
+func findConfig(dir string) (string, bool) {
+	for _, configName := range []string{"tsconfig.json", "jsconfig.json"} {
+		configPath := filepath.Join(dir, configName)
+		if info, err := os.Stat(configPath); err == nil && !info.IsDir() {
+			return configPath, true
+		}
+	}
+	return "", false
+}
+
+// ResolveAlias returns the module an alias imported by the file at filePath
+// points to, without extension nor index file. Only the alias with the
+// highest precedence matching importPath is used, its targets being tried in
+// order until one exists. Alias tables are tried by precedence, and `#` import
+// paths may also be subpath imports of the closest package.json. Other
+// non-relative import paths are resolved from baseUrl, such as
+// `components/forms` with `"baseUrl": "src"`, unless they are packages.
+func (resolver *Resolver) ResolveAlias(filePath string, importPath string) (string, bool) {
+	if IsRelativeImportPath(importPath) {
+		return "", false
+	}
+	for _, aliases := range resolver.aliasTables(filePath) {
+		if path, exists := resolveAliases(aliases, importPath); exists {
+			return getModulePath(path), true
+		}
+	}
+	if strings.HasPrefix(importPath, "#") {
+		path, exists := resolveAliases(resolver.subpathImports(filePath), importPath)
+		return getModulePath(path), exists
+	}
+
+	config := resolver.fileConfig(filePath)
+	if config.baseUrl == "" || isPackage(filePath, importPath) {
+		return "", false
+	}
+	path := filepath.Join(config.baseUrl, importPath)
+	return getModulePath(path), moduleExists(path)
+}
+
+// aliasTables returns the aliases of the file at filePath by precedence:
+// tsconfig `paths`, the import map, workspace packages, then the aliases of
+// tool configs.
+func (resolver *Resolver) aliasTables(filePath string) [][]aliasPattern {
+	return [][]aliasPattern{resolver.fileConfig(filePath).aliases, resolver.importMapAliases(filePath), resolver.workspaces.aliases, resolver.toolAliases}
+}
+
+// resolveAliases returns the first existing target of the alias with the
+// highest precedence matching importPath.
+func resolveAliases(aliases []aliasPattern, importPath string) (string, bool) {
+	for _, alias := range aliases {
+		wildcard, matches := alias.match(importPath)
+		if !matches {
+			continue
+		}
+		for _, target := range alias.targets {
+			path := strings.Replace(target, "*", wildcard, 1)
+			if moduleExists(path) {
+				return path, true
 			}
 		}
+		return "", false
 	}
+	return "", false
+}
 
-	return aliasPaths
+// AliasPath returns the import path of the module at path through the aliases
+// of the file at filePath, preferring the most specific target.
+func (resolver *Resolver) AliasPath(filePath string, path string) (string, bool) {
+	for _, aliases := range resolver.aliasTables(filePath) {
+		if aliasPath, exists := reverseAliases(aliases, path); exists {
+			return aliasPath, true
+		}
+	}
+	if config := resolver.fileConfig(filePath); config.baseUrl != "" {
+		if relativePath, err := filepath.Rel(config.baseUrl, path); err == nil && !strings.HasPrefix(relativePath, "..") {
+			return filepath.ToSlash(relativePath), true
+		}
+	}
+	return "", false
 }
 
-type Alias struct {
-	ShortPath string
-	FullPath  string
+// SubpathImportPath returns the `#` import path of the module at path through
+// the subpath imports of the package.json closest to the file at filePath.
+func (resolver *Resolver) SubpathImportPath(filePath string, path string) (string, bool) {
+	return reverseAliases(resolver.subpathImports(filePath), path)
 }
 
-func (resolver *Resolver) AliasPath(path string) Alias {
-	for realPath, alias := range resolver.aliasPaths {
-		if strings.HasPrefix(path, realPath) {
-			fullPath := filepath.Join(alias, strings.TrimPrefix(path, realPath))
-			return Alias{
-				ShortPath: alias,
-				FullPath:  fullPath,
+// reverseAliases returns the import path of the module at path, given without
+// extension nor index file, through the alias with the most specific target.
+func reverseAliases(aliases []aliasPattern, path string) (string, bool) {
+	aliasPath, matchLength := "", -1
+	for _, alias := range aliases {
+		for _, target := range alias.targets {
+			targetPrefix, targetSuffix, hasWildcard := strings.Cut(target, "*")
+			// "#ui/*": "./src/ui/*.js" points to ./src/ui/button for #ui/button
+			targetSuffix = trimModuleExtension(targetSuffix)
+			switch {
+			case !hasWildcard && getModulePath(target) == path && len(target) > matchLength:
+				aliasPath, matchLength = alias.key, len(target)
+			case hasWildcard && alias.hasWildcard && len(targetPrefix) > matchLength:
+				if len(path) <= len(targetPrefix)+len(targetSuffix) || !strings.HasPrefix(path, targetPrefix) || !strings.HasSuffix(path, targetSuffix) {
+					continue
+				}
+				wildcard := path[len(targetPrefix) : len(path)-len(targetSuffix)]
+				wildcardAliasPath := alias.prefix + filepath.ToSlash(wildcard) + alias.suffix
+				if !isExcluded(aliases, wildcardAliasPath) {
+					aliasPath, matchLength = wildcardAliasPath, len(targetPrefix)
+				}
 			}
 		}
 	}
+	return aliasPath, matchLength >= 0
+}
+
+// isExcluded reports whether the alias with the highest precedence matching
+// importPath has no target, such as `"./internal/*": null` in `exports`.
+func isExcluded(aliases []aliasPattern, importPath string) bool {
+	for _, alias := range aliases {
+		if _, matches := alias.match(importPath); matches {
+			return len(alias.targets) == 0
+		}
+	}
+	return false
+}
 
-	return Alias{
-		ShortPath: path,
-		FullPath:  path,
+// getModulePath returns the path of a module without extension nor index file
+func getModulePath(path string) string {
+	path = trimModuleExtension(path)
+	if filepath.Base(path) == "index" {
+		path = filepath.Dir(path)
+	}
+	return path
+}
+
+func trimModuleExtension(path string) string {
+	for _, extension := range moduleExtensions {
+		if strings.HasSuffix(path, extension) {
+			return strings.TrimSuffix(path, extension)
+		}
+	}
+	return path
+}
+
+// IsRelativeImportPath reports whether importPath is a path rather than a module name
+func IsRelativeImportPath(importPath string) bool {
+	return importPath == "." || importPath == ".." || strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") || filepath.IsAbs(importPath)
+}
+
+// isPackage reports whether importPath imports a package installed in a
+// node_modules directory of the file at filePath or of its parents.
+func isPackage(filePath string, importPath string) bool {
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return false
+	}
+	packageName := getPackageName(importPath)
+	for ; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "node_modules", packageName)); err == nil {
+			return true
+		}
+		if filepath.Dir(dir) == dir {
+			return false
+		}
+	}
+}
+
+// getPackageName returns the package of an import path, such as `@scope/name` for `@scope/name/subpath`
+func getPackageName(importPath string) string {
+	segments := strings.SplitN(importPath, "/", 3)
+	if strings.HasPrefix(importPath, "@") && len(segments) > 1 {
+		return segments[0] + "/" + segments[1]
+	}
+	return segments[0]
+}
+
+// moduleExists reports whether a module exists at path, which may omit its
+// extension or use a JavaScript extension for a TypeScript file.
+func moduleExists(path string) bool {
+	if _, err := os.Stat(path); err == nil {
+		return true
+	}
+	for _, modulePath := range []string{path, trimModuleExtension(path)} {
+		for _, extension := range moduleExtensions {
+			if _, err := os.Stat(modulePath + extension); err == nil {
+				return true
+			}
+		}
 	}
+	return false
 }
@@ -0,0 +1,110 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAlias(t *testing.T) {
+	rootPath := t.TempDir()
+	for _, path := range []string{"src/ui/button.ts", "src/ui/legacy/card.ts", "generated/api/client.ts", "src/ui/index.ts"} {
+		os.MkdirAll(filepath.Join(rootPath, filepath.Dir(path)), 0755)
+		os.WriteFile(filepath.Join(rootPath, path), []byte("export {};\n"), 0644)
+	}
+	os.WriteFile(filepath.Join(rootPath, "tsconfig.json"), []byte(`{
+  "compilerOptions": {
+    "paths": {
+      "@ui": ["./src/ui/index.ts"],
+      "@ui/*": ["./src/ui/*"],
+      "@ui/legacy/*": ["./src/ui/legacy/*"],
+      "*": ["./src/*", "./generated/*"]
+    }
+  }
+}`), 0644)
+	tsConfigPath := "tsconfig.json"
+	resolver := New(rootPath, &tsConfigPath, nil, false)
+	filePath := filepath.Join(rootPath, "src", "app.ts")
+
+	resolvedPath, isAlias := resolver.ResolveAlias(filePath, "@ui")
+	assert.True(t, isAlias)
+	assert.Equal(t, filepath.Join(rootPath, "src/ui"), resolvedPath)
+	resolvedPath, _ = resolver.ResolveAlias(filePath, "api/client")
+	assert.Equal(t, filepath.Join(rootPath, "generated/api/client"), resolvedPath)
+	_, isAlias = resolver.ResolveAlias(filePath, "@tanstack/query")
+	assert.False(t, isAlias)
+
+	aliasPath, _ := resolver.AliasPath(filePath, filepath.Join(rootPath, "src/ui/legacy/card"))
+	assert.Equal(t, "@ui/legacy/card", aliasPath)
+	aliasPath, _ = resolver.AliasPath(filePath, filepath.Join(rootPath, "src/ui"))
+	assert.Equal(t, "@ui", aliasPath)
+	aliasPath, _ = resolver.AliasPath(filePath, filepath.Join(rootPath, "src/ui/button"))
+	assert.Equal(t, "@ui/button", aliasPath)
+}
+
+func TestConvertRegexpAlias(t *testing.T) {
+	key, targets, ok := convertRegexpAlias(`^@ui\/(.*)$`, []jsValue{{kind: jsString, text: "./src/ui/$1"}}, "$1")
+	assert.True(t, ok)
+	assert.Equal(t, "@ui/*", key)
+	assert.Equal(t, "./src/ui/*", targets[0].text)
+	key, _, ok = convertRegexpAlias("^@ui$", []jsValue{{kind: jsString, text: "./src/ui"}}, "$1")
+	assert.True(t, ok)
+	assert.Equal(t, "@ui", key)
+	_, _, ok = convertRegexpAlias(`\.(css|png)$`, []jsValue{{kind: jsString, text: "./mocks/file.js"}}, "$1")
+	assert.False(t, ok)
+	_, _, ok = convertRegexpAlias(`^(\.{1,2}/.*)\.js$`, []jsValue{{kind: jsString, text: "$1"}}, "$1")
+	assert.False(t, ok)
+}
+
+func TestLoadCompilerOptionsDiamondExtends(t *testing.T) {
+	rootPath := t.TempDir()
+	os.MkdirAll(filepath.Join(rootPath, "config"), 0755)
+	os.WriteFile(filepath.Join(rootPath, "tsconfig.json"), []byte(`{ "extends": ["./config/b.json", "./config/a.json"] }`), 0644)
+	os.WriteFile(filepath.Join(rootPath, "config/base.json"), []byte(`{
+  "compilerOptions": { "baseUrl": "..", "paths": { "@ui/*": ["src/ui/*"] } }
+}`), 0644)
+	os.WriteFile(filepath.Join(rootPath, "config/a.json"), []byte(`{
+  "extends": "./base.json",
+  "compilerOptions": { "rootDirs": ["../src", "../generated"] }
+}`), 0644)
+	os.WriteFile(filepath.Join(rootPath, "config/b.json"), []byte(`{
+  "extends": "./base.json",
+  "compilerOptions": { "paths": { "@legacy/*": ["legacy/*"] } }
+}`), 0644)
+
+	options, err := loadCompilerOptions(filepath.Join(rootPath, "tsconfig.json"), make(map[string]struct{}))
+	assert.NoError(t, err)
+	assert.Equal(t, rootPath, options.baseUrl)
+	// base.json, extended by a.json, overrides the paths of b.json
+	assert.Equal(t, map[string][]string{"@ui/*": {"src/ui/*"}}, options.paths)
+	assert.Equal(t, []string{filepath.Join(rootPath, "src"), filepath.Join(rootPath, "generated")}, options.rootDirs)
+}
+
+func TestLoadImportMapScopes(t *testing.T) {
+	rootPath := t.TempDir()
+	os.WriteFile(filepath.Join(rootPath, "import_map.json"), []byte(`{
+  "scopes": {
+    "./": {},
+    "./src/": {},
+    "./lib/": {},
+    "./src/app.ts": {},
+    "./lib/app.ts": {}
+  }
+}`), 0644)
+
+	importMap, err := loadImportMap(filepath.Join(rootPath, "import_map.json"))
+	assert.NoError(t, err)
+	scopePaths := []string{}
+	for _, scope := range importMap.scopes {
+		scopePaths = append(scopePaths, scope.path)
+	}
+	assert.Equal(t, []string{
+		filepath.Join(rootPath, "lib/app.ts"),
+		filepath.Join(rootPath, "src/app.ts"),
+		filepath.Join(rootPath, "lib"),
+		filepath.Join(rootPath, "src"),
+		rootPath,
+	}, scopePaths)
+}
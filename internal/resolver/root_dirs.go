@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RootDirs returns the rootDirs of the alias config of the file at filePath,
+// which TypeScript merges into one virtual directory.
+func (resolver *Resolver) RootDirs(filePath string) []string {
+	return resolver.fileConfig(filePath).rootDirs
+}
+
+// RootDirsPath returns the path of the module a relative import of the file at
+// filePath points to: path itself when a module exists there, else the first
+// of its equivalents in the other rootDirs where one exists.
+func (resolver *Resolver) RootDirsPath(filePath string, path string) string {
+	for _, rootDirPath := range RootDirPaths(path, resolver.fileConfig(filePath).rootDirs) {
+		if moduleFileExists(rootDirPath) {
+			return rootDirPath
+		}
+	}
+	return path
+}
+
+// RootDirPaths returns the paths path may point to when rootDirs are merged
+// into one virtual directory: path itself, then the same relative path in each
+// other rootDir, in order.
+func RootDirPaths(path string, rootDirs []string) []string {
+	paths := []string{path}
+	rootDir, exists := getRootDir(path, rootDirs)
+	if !exists {
+		return paths
+	}
+	relativePath, err := filepath.Rel(rootDir, path)
+	if err != nil {
+		return paths
+	}
+	for _, otherRootDir := range rootDirs {
+		if otherRootDir != rootDir {
+			paths = append(paths, filepath.Join(otherRootDir, relativePath))
+		}
+	}
+	return paths
+}
+
+// VirtualPath returns path as if it was in the rootDir of referencePath, so
+// that the relative path between them is the one within the virtual directory.
+func VirtualPath(path string, referencePath string, rootDirs []string) string {
+	rootDir, exists := getRootDir(path, rootDirs)
+	referenceRootDir, referenceExists := getRootDir(referencePath, rootDirs)
+	if !exists || !referenceExists || rootDir == referenceRootDir {
+		return path
+	}
+	relativePath, err := filepath.Rel(rootDir, path)
+	if err != nil {
+		return path
+	}
+	return filepath.Join(referenceRootDir, relativePath)
+}
+
+// getRootDir returns the most specific rootDir containing path
+func getRootDir(path string, rootDirs []string) (string, bool) {
+	rootDir := ""
+	for _, dir := range rootDirs {
+		if (path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))) && len(dir) > len(rootDir) {
+			rootDir = dir
+		}
+	}
+	return rootDir, rootDir != ""
+}
+
+// moduleFileExists reports whether a module file exists at path, with or
+// without extension, or as the index file of the directory at path.
+func moduleFileExists(path string) bool {
+	for _, modulePath := range []string{path, filepath.Join(path, "index")} {
+		if info, err := os.Stat(modulePath); err == nil && !info.IsDir() {
+			return true
+		}
+		for _, extension := range moduleExtensions {
+			if info, err := os.Stat(modulePath + extension); err == nil && !info.IsDir() {
+				return true
+			}
+		}
+	}
+	return false
+}
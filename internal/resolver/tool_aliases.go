@@ -0,0 +1,227 @@
+package resolver
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// aliasSource is a tool declaring aliases in a property of its config, the
+// first of its configs found at the root path being used.
+type aliasSource struct {
+	configNames []string
+	property    string
+	parse       func(value jsValue, configDir string) []aliasPattern
+}
+
+// aliasSources are the tools aliases are read from, by precedence
+var aliasSources = []aliasSource{
+	{
+		// plugins: [['module-resolver', { alias: { '@ui': './src/ui' } }]]
+		configNames: []string{".babelrc", ".babelrc.json", "babel.config.json", "babel.config.js", "babel.config.cjs", "babel.config.mjs"},
+		property:    "alias",
+		parse:       parseBabelAliases,
+	},
+	{
+		// "moduleNameMapper": { "^@ui/(.*)$": "<rootDir>/src/ui/$1" }
+		configNames: []string{"jest.config.json", "jest.config.js", "jest.config.cjs", "jest.config.mjs", "jest.config.ts", "package.json"},
+		property:    "moduleNameMapper",
+		parse:       parseJestAliases,
+	},
+	{
+		// resolve: { alias: { '@ui': path.resolve(__dirname, './src/ui') } }
+		configNames: []string{"vite.config.ts", "vite.config.js", "vite.config.mts", "vite.config.mjs", "vite.config.cts", "vite.config.cjs"},
+		property:    "alias",
+		parse:       parseViteAliases,
+	},
+	{
+		// resolve: { alias: { '@ui': path.resolve(__dirname, 'src/ui') } }
+		configNames: []string{"webpack.config.js", "webpack.config.cjs", "webpack.config.mjs", "webpack.config.ts"},
+		property:    "alias",
+		parse:       parseWebpackAliases,
+	},
+}
+
+// getToolAliases returns the aliases declared by the Babel, Jest, Vite and
+// webpack configs of the root path, merged in this order of precedence.
+func getToolAliases(rootPath string) []aliasPattern {
+	aliases := []aliasPattern{}
+	for _, source := range aliasSources {
+		for _, configName := range source.configNames {
+			value, exists := readJSConfigProperty(filepath.Join(rootPath, configName), source.property)
+			if !exists {
+				continue
+			}
+			sourceAliases := source.parse(value, rootPath)
+			sortAliases(sourceAliases)
+			aliases = append(aliases, sourceAliases...)
+			break
+		}
+	}
+	return aliases
+}
+
+// parseBabelAliases parses the aliases of babel-plugin-module-resolver, where
+// keys starting with `^` are regular expressions substituting `\1`.
+func parseBabelAliases(value jsValue, configDir string) []aliasPattern {
+	aliases := []aliasPattern{}
+	for _, property := range value.properties {
+		if property.value.kind != jsString && property.value.kind != jsPath {
+			continue
+		}
+		if !strings.HasPrefix(property.key, "^") {
+			aliases = append(aliases, prefixAliasPatterns(property.key, property.value, configDir)...)
+			continue
+		}
+		if key, targets, ok := convertRegexpAlias(property.key, []jsValue{property.value}, `\1`); ok {
+			aliases = appendAliasPattern(aliases, key, targets, configDir)
+		}
+	}
+	return aliases
+}
+
+// parseJestAliases parses moduleNameMapper, whose keys are regular expressions
+// substituting `$1` in a target or in an array of fallback targets.
+func parseJestAliases(value jsValue, configDir string) []aliasPattern {
+	aliases := []aliasPattern{}
+	for _, property := range value.properties {
+		targets := []jsValue{property.value}
+		if property.value.kind == jsArray {
+			targets = property.value.elements
+		}
+		for i, target := range targets {
+			if rootDirPath, isRootDirPath := strings.CutPrefix(target.text, "<rootDir>"); target.kind == jsString && isRootDirPath {
+				targets[i] = jsValue{kind: jsPath, text: filepath.Join(configDir, rootDirPath)}
+			}
+		}
+		if key, targets, ok := convertRegexpAlias(property.key, targets, "$1"); ok {
+			aliases = appendAliasPattern(aliases, key, targets, configDir)
+		}
+	}
+	return aliases
+}
+
+// parseViteAliases parses `resolve.alias`, either an object or an array of
+// `{ find, replacement }` entries. Targets starting with `/` are relative to
+// the project root.
+func parseViteAliases(value jsValue, configDir string) []aliasPattern {
+	properties := value.properties
+	for _, element := range value.elements {
+		var find, replacement jsValue
+		for _, property := range element.properties {
+			switch property.key {
+			case "find":
+				find = property.value
+			case "replacement":
+				replacement = property.value
+			}
+		}
+		// Regular expressions can't be read statically
+		if find.kind == jsString {
+			properties = append(properties, jsProperty{key: find.text, value: replacement})
+		}
+	}
+
+	aliases := []aliasPattern{}
+	for _, property := range properties {
+		target := property.value
+		if target.kind == jsString && strings.HasPrefix(target.text, "/") {
+			target = jsValue{kind: jsPath, text: filepath.Join(configDir, target.text)}
+		}
+		aliases = append(aliases, prefixAliasPatterns(property.key, target, configDir)...)
+	}
+	return aliases
+}
+
+// parseWebpackAliases parses `resolve.alias`, where keys ending with `$` only
+// match exact import paths.
+func parseWebpackAliases(value jsValue, configDir string) []aliasPattern {
+	aliases := []aliasPattern{}
+	for _, property := range value.properties {
+		if key, isExact := strings.CutSuffix(property.key, "$"); isExact {
+			aliases = appendAliasPattern(aliases, key, []jsValue{property.value}, configDir)
+			continue
+		}
+		aliases = append(aliases, prefixAliasPatterns(property.key, property.value, configDir)...)
+	}
+	return aliases
+}
+
+// prefixAliasPatterns returns the aliases of a module name as Babel and
+// bundlers match it: `@ui` matches `@ui` and the import paths starting with `@ui/`.
+func prefixAliasPatterns(key string, target jsValue, configDir string) []aliasPattern {
+	aliases := appendAliasPattern(nil, key, []jsValue{target}, configDir)
+	if len(aliases) == 0 || strings.Contains(key, "*") {
+		return aliases
+	}
+	target.text = strings.TrimSuffix(target.text, "/") + "/*"
+	return appendAliasPattern(aliases, key+"/*", []jsValue{target}, configDir)
+}
+
+// appendAliasPattern appends the alias of key to aliases unless none of its
+// targets is a path, `"lodash": "lodash-es"` aliasing packages.
+func appendAliasPattern(aliases []aliasPattern, key string, targets []jsValue, configDir string) []aliasPattern {
+	paths := []string{}
+	for _, target := range targets {
+		switch {
+		case target.kind == jsPath || (target.kind == jsString && filepath.IsAbs(target.text)):
+			paths = append(paths, target.text)
+		case target.kind == jsString && IsRelativeImportPath(target.text):
+			paths = append(paths, filepath.Join(configDir, target.text))
+		}
+	}
+	if len(paths) == 0 {
+		return aliases
+	}
+	return append(aliases, newAliasPattern(key, paths, ""))
+}
+
+// convertRegexpAlias converts a regular expression alias such as `^@ui/(.*)$`
+// to `./src/ui/$1` into the equivalent `@ui/*` to `./src/ui/*` alias. Other
+// regular expressions can't be turned back into import paths and are ignored.
+func convertRegexpAlias(pattern string, targets []jsValue, reference string) (string, []jsValue, bool) {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")
+	prefix, suffix, hasWildcard := pattern, "", false
+	for _, group := range []string{"(.*)", "(.+)"} {
+		if before, after, found := strings.Cut(pattern, group); found {
+			prefix, suffix, hasWildcard = before, after, true
+			break
+		}
+	}
+	prefix, isPrefixLiteral := unescapeRegexp(prefix)
+	suffix, isSuffixLiteral := unescapeRegexp(suffix)
+	if !isPrefixLiteral || !isSuffixLiteral {
+		return "", nil, false
+	}
+	if !hasWildcard {
+		return prefix, targets, true
+	}
+
+	wildcardTargets := []jsValue{}
+	for _, target := range targets {
+		if strings.Count(target.text, reference) == 1 {
+			target.text = strings.Replace(target.text, reference, "*", 1)
+			wildcardTargets = append(wildcardTargets, target)
+		}
+	}
+	return prefix + "*" + suffix, wildcardTargets, len(wildcardTargets) > 0
+}
+
+// unescapeRegexp returns the literal matched by a regular expression without
+// any metacharacter, such as `@ui\/forms` for `@ui/forms`.
+func unescapeRegexp(pattern string) (string, bool) {
+	var literal strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern) && !isAlphanumeric(pattern[i+1]):
+			i++
+		case strings.IndexByte(`\.*+?()[]{}|^$`, pattern[i]) >= 0:
+			return "", false
+		}
+		literal.WriteByte(pattern[i])
+	}
+	return literal.String(), true
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,178 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+type TSConfig struct {
+	Extends         ExtendsPaths `json:"extends"`
+	CompilerOptions struct {
+		Paths    map[string][]string `json:"paths"`
+		BaseUrl  *string             `json:"baseUrl"`
+		RootDirs []string            `json:"rootDirs"`
+	} `json:"compilerOptions"`
+}
+
+// ExtendsPaths are the configs extended by a tsconfig, `extends` being either a string or an array
+type ExtendsPaths []string
+
+func (extendsPaths *ExtendsPaths) UnmarshalJSON(data []byte) error {
+	var extendsPath string
+	if err := json.Unmarshal(data, &extendsPath); err == nil {
+		*extendsPaths = ExtendsPaths{extendsPath}
+		return nil
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return err
+	}
+	*extendsPaths = paths
+	return nil
+}
+
+// compilerOptions are the compiler options of a tsconfig merged with the
+// configs it extends, with paths made absolute.
+type compilerOptions struct {
+	baseUrl string
+	paths   map[string][]string
+	// pathsBasePath is the directory `paths` are relative to: baseUrl, or the
+	// directory of the config declaring `paths` when baseUrl isn't set
+	pathsBasePath string
+	rootDirs      []string
+}
+
+// loadCompilerOptions reads the tsconfig at configPath and the configs it
+// extends. Options of a config override the ones of the configs it extends,
+// later entries of an `extends` array overriding earlier ones.
+func loadCompilerOptions(configPath string, visited map[string]struct{}) (compilerOptions, error) {
+	if _, isVisited := visited[configPath]; isVisited {
+		return compilerOptions{}, fmt.Errorf("circular extends in %s", configPath)
+	}
+	// Only the configs being loaded are visited, a config extended by two
+	// branches of an `extends` array not being circular
+	visited[configPath] = struct{}{}
+	defer delete(visited, configPath)
+
+	tsConfig, err := readTSConfig(configPath)
+	if err != nil {
+		return compilerOptions{}, err
+	}
+
+	options := compilerOptions{}
+	configDir := filepath.Dir(configPath)
+	for _, extendsPath := range tsConfig.Extends {
+		extendedConfigPath, exists := resolveExtendsPath(configDir, extendsPath)
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Unable to find tsconfig %s extended by %s\n", extendsPath, configPath)
+			continue
+		}
+		extendedOptions, err := loadCompilerOptions(extendedConfigPath, visited)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing tsconfig %s: %v\n", extendedConfigPath, err)
+			continue
+		}
+		options = options.merge(extendedOptions)
+	}
+
+	declaredOptions := compilerOptions{paths: tsConfig.CompilerOptions.Paths}
+	if tsConfig.CompilerOptions.BaseUrl != nil {
+		declaredOptions.baseUrl = filepath.Join(configDir, *tsConfig.CompilerOptions.BaseUrl)
+	}
+	if declaredOptions.paths != nil {
+		declaredOptions.pathsBasePath = configDir
+	}
+	for _, rootDir := range tsConfig.CompilerOptions.RootDirs {
+		declaredOptions.rootDirs = append(declaredOptions.rootDirs, filepath.Join(configDir, rootDir))
+	}
+	return options.merge(declaredOptions), nil
+}
+
+// merge returns the options overridden by the options set in overrides
+func (options compilerOptions) merge(overrides compilerOptions) compilerOptions {
+	if overrides.baseUrl != "" {
+		options.baseUrl = overrides.baseUrl
+	}
+	if overrides.paths != nil {
+		options.paths = overrides.paths
+		options.pathsBasePath = overrides.pathsBasePath
+	}
+	if overrides.rootDirs != nil {
+		options.rootDirs = overrides.rootDirs
+	}
+	return options
+}
+
+// pathsBaseUrl returns the directory the targets of `paths` are relative to
+func (options compilerOptions) pathsBaseUrl() string {
+	if options.baseUrl != "" {
+		return options.baseUrl
+	}
+	return options.pathsBasePath
+}
+
+func readTSConfig(configPath string) (TSConfig, error) {
+	file, err := os.ReadFile(configPath)
+	if err != nil {
+		return TSConfig{}, err
+	}
+	b, err := hujson.Standardize(file)
+	if err != nil {
+		return TSConfig{}, err
+	}
+	var tsConfig TSConfig
+	if err := json.Unmarshal(b, &tsConfig); err != nil {
+		return TSConfig{}, err
+	}
+	return tsConfig, nil
+}
+
+// resolveExtendsPath resolves an `extends` entry, either relative to the
+// extending config or a config provided by a package such as `@tsconfig/node20`.
+func resolveExtendsPath(configDir string, extendsPath string) (string, bool) {
+	if filepath.IsAbs(extendsPath) || strings.HasPrefix(extendsPath, "./") || strings.HasPrefix(extendsPath, "../") {
+		return resolveConfigFile(filepath.Join(configDir, extendsPath))
+	}
+
+	for dir := configDir; ; dir = filepath.Dir(dir) {
+		packagePath := filepath.Join(dir, "node_modules", extendsPath)
+		if configPath, exists := resolveConfigFile(packagePath); exists {
+			return configPath, true
+		}
+		if configPath, exists := resolvePackageConfig(packagePath); exists {
+			return configPath, true
+		}
+		if filepath.Dir(dir) == dir {
+			return "", false
+		}
+	}
+}
+
+// resolveConfigFile resolves a config path which may omit the `.json` extension
+func resolveConfigFile(path string) (string, bool) {
+	for _, configPath := range []string{path, path + ".json"} {
+		if info, err := os.Stat(configPath); err == nil && !info.IsDir() {
+			return configPath, true
+		}
+	}
+	return "", false
+}
+
+// resolvePackageConfig resolves the config of a package directory, declared by
+// the `tsconfig` field of its package.json or defaulting to its tsconfig.json.
+func resolvePackageConfig(packagePath string) (string, bool) {
+	if file, err := os.ReadFile(filepath.Join(packagePath, "package.json")); err == nil {
+		var packageJSON struct {
+			TSConfig string `json:"tsconfig"`
+		}
+		if json.Unmarshal(file, &packageJSON) == nil && packageJSON.TSConfig != "" {
+			return resolveConfigFile(filepath.Join(packagePath, packageJSON.TSConfig))
+		}
+	}
+	return resolveConfigFile(filepath.Join(packagePath, "tsconfig.json"))
+}
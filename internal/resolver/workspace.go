@@ -0,0 +1,253 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// packageJSON is the part of a package.json describing the entry points of a
+// package, and the workspaces of a monorepo root.
+type packageJSON struct {
+	Name       string          `json:"name"`
+	Main       string          `json:"main"`
+	Module     string          `json:"module"`
+	Types      string          `json:"types"`
+	Typings    string          `json:"typings"`
+	Exports    json.RawMessage `json:"exports"`
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+// workspaces are the packages of a monorepo, imported by name from each other
+type workspaces struct {
+	// aliases map package names and the subpaths they export to their modules
+	aliases      []aliasPattern
+	packageNames map[string]struct{}
+}
+
+// getWorkspaces returns the packages of the workspaces declared at the root
+// path by pnpm-workspace.yaml or by the `workspaces` of package.json, along
+// with the errors of the configs ignored as they failed to load.
+func getWorkspaces(rootPath string) (workspaces, []error) {
+	result := workspaces{packageNames: make(map[string]struct{})}
+	patterns, err := getWorkspacePatterns(rootPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing workspace config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Ignoring workspace config\n")
+		return result, []error{err}
+	}
+	if len(patterns) == 0 {
+		return result, nil
+	}
+
+	loadErrors := []error{}
+	for _, packageDir := range findWorkspacePackages(rootPath, patterns) {
+		packageName, aliases, err := getPackageAliases(packageDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing workspace package: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Ignoring workspace package\n")
+			loadErrors = append(loadErrors, err)
+			continue
+		}
+		if packageName == "" {
+			continue
+		}
+		result.packageNames[packageName] = struct{}{}
+		result.aliases = append(result.aliases, aliases...)
+	}
+	sortAliases(result.aliases)
+	return result, loadErrors
+}
+
+// getWorkspacePatterns returns the globs of the workspace packages, such as `packages/*`
+func getWorkspacePatterns(rootPath string) ([]string, error) {
+	pnpmWorkspacePath := filepath.Join(rootPath, "pnpm-workspace.yaml")
+	if file, err := os.ReadFile(pnpmWorkspacePath); err == nil {
+		var pnpmWorkspace struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal(file, &pnpmWorkspace); err != nil {
+			return nil, fmt.Errorf("unable to load the workspaces %s: %w", pnpmWorkspacePath, err)
+		}
+		return pnpmWorkspace.Packages, nil
+	}
+
+	packagePath := filepath.Join(rootPath, "package.json")
+	file, err := os.ReadFile(packagePath)
+	if err != nil {
+		return nil, nil
+	}
+	var rootPackage packageJSON
+	if err := json.Unmarshal(file, &rootPackage); err != nil {
+		return nil, fmt.Errorf("unable to load the workspaces %s: %w", packagePath, err)
+	}
+	if len(rootPackage.Workspaces) == 0 {
+		return nil, nil
+	}
+	// "workspaces" is either an array of globs or, with Yarn, an object of them
+	var patterns []string
+	if json.Unmarshal(rootPackage.Workspaces, &patterns) == nil {
+		return patterns, nil
+	}
+	var yarnWorkspaces struct {
+		Packages []string `json:"packages"`
+	}
+	json.Unmarshal(rootPackage.Workspaces, &yarnWorkspaces)
+	return yarnWorkspaces.Packages, nil
+}
+
+// findWorkspacePackages returns the directories of the packages matching the
+// workspace globs, globs starting with `!` excluding directories.
+func findWorkspacePackages(rootPath string, patterns []string) []string {
+	var includes, excludes []*regexp.Regexp
+	for _, pattern := range patterns {
+		if excludedPattern, isExcluded := strings.CutPrefix(pattern, "!"); isExcluded {
+			excludes = append(excludes, workspacePatternRegexp(excludedPattern))
+		} else {
+			includes = append(includes, workspacePatternRegexp(pattern))
+		}
+	}
+
+	packageDirs := []string{}
+	filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() || path == rootPath {
+			return nil
+		}
+		if entry.Name() == "node_modules" || strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		relativePath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return nil
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if matchesAny(includes, relativePath) && !matchesAny(excludes, relativePath) {
+			if _, err := os.Stat(filepath.Join(path, "package.json")); err == nil {
+				packageDirs = append(packageDirs, path)
+			}
+		}
+		return nil
+	})
+	return packageDirs
+}
+
+// workspacePatternRegexp converts a workspace glob to a regular expression
+// matching directory paths relative to the root path.
+func workspacePatternRegexp(pattern string) *regexp.Regexp {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+	var expression strings.Builder
+	expression.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expression.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expression.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expression.WriteString("$")
+	return regexp.MustCompile(expression.String())
+}
+
+func matchesAny(expressions []*regexp.Regexp, path string) bool {
+	for _, expression := range expressions {
+		if expression.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPackageAliases returns the name of the package at packageDir and the
+// aliases of its entry points. Only the subpaths of `exports` can be imported
+// when it is set, any module of the package otherwise.
+func getPackageAliases(packageDir string) (string, []aliasPattern, error) {
+	packagePath := filepath.Join(packageDir, "package.json")
+	file, err := os.ReadFile(packagePath)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to load the workspace package %s: %w", packagePath, err)
+	}
+	var packageJSON packageJSON
+	if err := json.Unmarshal(file, &packageJSON); err != nil {
+		return "", nil, fmt.Errorf("unable to load the workspace package %s: %w", packagePath, err)
+	}
+	if packageJSON.Name == "" {
+		return "", nil, nil
+	}
+	if len(packageJSON.Exports) > 0 && string(packageJSON.Exports) != "null" {
+		return packageJSON.Name, getExportsAliases(packageJSON.Name, packageJSON.Exports, packageDir), nil
+	}
+
+	entryPoints := []string{}
+	for _, entryPoint := range []string{packageJSON.Types, packageJSON.Typings, packageJSON.Module, packageJSON.Main, "./index"} {
+		if entryPoint != "" {
+			entryPoints = append(entryPoints, entryPoint)
+		}
+	}
+	return packageJSON.Name, []aliasPattern{
+		newAliasPattern(packageJSON.Name, entryPoints, packageDir),
+		newAliasPattern(packageJSON.Name+"/*", []string{"./*"}, packageDir),
+	}, nil
+}
+
+// getExportsAliases returns the aliases of the subpaths exported by a package,
+// `exports` being either an object of subpaths or the target of `.`. Subpaths
+// with a null target, such as `"./internal/*": null`, are excluded: their
+// aliases have no target.
+func getExportsAliases(packageName string, exports json.RawMessage, packageDir string) []aliasPattern {
+	subpaths := map[string]json.RawMessage{}
+	if json.Unmarshal(exports, &subpaths) != nil || !hasSubpathKeys(subpaths) {
+		// "exports": "./index.js" or "exports": { "import": "./index.js" }
+		subpaths = map[string]json.RawMessage{".": exports}
+	}
+
+	aliases := []aliasPattern{}
+	for subpath, target := range subpaths {
+		if subpath != "." && !strings.HasPrefix(subpath, "./") {
+			continue
+		}
+		if string(target) == "null" {
+			aliases = append(aliases, newAliasPattern(packageName+strings.TrimPrefix(subpath, "."), nil, packageDir))
+			continue
+		}
+		targets := []string{}
+		for _, subpathTarget := range getSubpathTargets(target) {
+			if strings.HasPrefix(subpathTarget, "./") {
+				targets = append(targets, subpathTarget)
+			}
+		}
+		if len(targets) > 0 {
+			aliases = append(aliases, newAliasPattern(packageName+strings.TrimPrefix(subpath, "."), targets, packageDir))
+		}
+	}
+	return aliases
+}
+
+func hasSubpathKeys(subpaths map[string]json.RawMessage) bool {
+	for subpath := range subpaths {
+		if strings.HasPrefix(subpath, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWorkspacePackage reports whether importPath imports a package of the workspaces
+func (resolver *Resolver) IsWorkspacePackage(importPath string) bool {
+	_, exists := resolver.workspaces.packageNames[getPackageName(importPath)]
+	return exists
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func ExecuteCommand(rootCmd *cobra.Command, args ...string) (string, error) {
+	resetFlags(rootCmd)
 	buf := new(bytes.Buffer)
 	rootCmd.SetOut(buf)
 	rootCmd.SetErr(buf)
@@ -15,3 +17,15 @@ func ExecuteCommand(rootCmd *cobra.Command, args ...string) (string, error) {
 	err := rootCmd.Execute()
 	return buf.String(), err
 }
+
+// resetFlags restores the default values of the flags of cmd and of its
+// subcommands, which cobra keeps from one execution to the next.
+func resetFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	})
+	for _, subcommand := range cmd.Commands() {
+		resetFlags(subcommand)
+	}
+}
@@ -0,0 +1,237 @@
+package tokenizer
+
+// parseRequire parses `const { a, b: c } = require('module')` and
+// `const module = require('module')` declarations as imports.
+func (p *statementParser) parseRequire() (Import, bool) {
+	start := p.index
+	statement := Import{Start: p.tokens[start].Start, Require: p.tokens[start].Value}
+	p.index++
+
+	switch {
+	case p.isKind(0, Identifier):
+		statement.Namespace = p.tokens[p.index].Value
+		p.index++
+	case p.isValue(0, "{"):
+		specifiers, ok := p.parseRequireProperties()
+		if !ok {
+			p.index = start
+			return Import{}, false
+		}
+		statement.HasNamed = true
+		statement.Specifiers = specifiers
+	}
+	if !p.isValue(0, "=") {
+		p.index = start
+		return Import{}, false
+	}
+	p.index++
+
+	source, quote, ok := p.parseRequireCall()
+	if !ok || !p.isStatementEnd() {
+		p.index = start
+		return Import{}, false
+	}
+	statement.Source, statement.Quote = source, quote
+	statement.Semicolon = p.skipSemicolon()
+	statement.End = p.previousEnd()
+	return statement, true
+}
+
+// parseRequireProperties parses `{ a, b: c }` object patterns, which are the
+// only ones that can be split across modules.
+func (p *statementParser) parseRequireProperties() ([]Specifier, bool) {
+	specifiers := []Specifier{}
+	p.index++
+	for p.index < len(p.tokens) && !p.isValue(0, "}") {
+		if !p.isKind(0, Identifier) {
+			return nil, false
+		}
+		specifier := Specifier{Name: p.tokens[p.index].Value, Alias: p.tokens[p.index].Value}
+		p.index++
+		if p.isValue(0, ":") {
+			if !p.isKind(1, Identifier) {
+				return nil, false
+			}
+			specifier.Alias = p.tokens[p.index+1].Value
+			p.index += 2
+		}
+		specifiers = append(specifiers, specifier)
+		if p.isValue(0, ",") {
+			p.index++
+		} else if !p.isValue(0, "}") {
+			return nil, false
+		}
+	}
+	p.index = min(p.index+1, len(p.tokens))
+	return specifiers, true
+}
+
+// parseRequireCall parses `require('module')` and returns the required module
+func (p *statementParser) parseRequireCall() (string, string, bool) {
+	if !p.isValue(0, "require") || !p.isValue(1, "(") || !p.isKind(2, String) || !p.isValue(3, ")") {
+		return "", "", false
+	}
+	source, quote := unquote(p.tokens[p.index+2].Value)
+	p.index += 4
+	return source, quote, true
+}
+
+// parseCommonJSExports parses `module.exports = ...`, `exports.a = ...` and
+// `Object.assign(module.exports, ...)` statements as exports.
+func (p *statementParser) parseCommonJSExports() ([]Export, bool) {
+	start := p.index
+	var exports []Export
+	switch {
+	case p.isValue(0, "Object") && p.isValue(1, ".") && p.isValue(2, "assign") && p.isValue(3, "("):
+		p.index += 4
+		if !p.parseExportsObject() {
+			p.index = start
+			return nil, false
+		}
+		for p.isValue(0, ",") {
+			p.index++
+			exports = append(exports, p.parseExportedValue("")...)
+		}
+		if !p.isValue(0, ")") {
+			p.index = start
+			return nil, false
+		}
+		p.index++
+	case p.parseExportsObject():
+		name := ""
+		if p.isValue(0, ".") && p.isKind(1, Identifier) {
+			name = p.tokens[p.index+1].Value
+			p.index += 2
+		}
+		if !p.isValue(0, "=") || p.isValue(1, "=") {
+			p.index = start
+			return nil, false
+		}
+		p.index++
+		exports = p.parseExportedValue(name)
+	}
+
+	if len(exports) == 0 {
+		p.index = start
+		return nil, false
+	}
+	semicolon := p.skipSemicolon()
+	for i := range exports {
+		exports[i].Start = p.tokens[start].Start
+		exports[i].End = p.previousEnd()
+		exports[i].Semicolon = semicolon
+	}
+	return exports, true
+}
+
+// parseExportsObject parses `module.exports` or `exports`
+func (p *statementParser) parseExportsObject() bool {
+	switch {
+	case p.isValue(0, "module") && p.isValue(1, ".") && p.isValue(2, "exports"):
+		p.index += 3
+		return true
+	case p.isValue(0, "exports"):
+		p.index++
+		return true
+	}
+	return false
+}
+
+// parseExportedValue parses a value assigned to the exported property name, or
+// merged into the exports when name is empty, such as `module.exports = value`.
+func (p *statementParser) parseExportedValue(name string) []Export {
+	start := p.index
+	if source, quote, ok := p.parseRequireCall(); ok {
+		if p.isExpressionEnd() {
+			if name == "" {
+				// module.exports = require('./module')
+				return []Export{{IsStar: true, Source: source, Quote: quote}}
+			}
+			// exports.a = require('./module')
+			return []Export{{IsStar: true, Namespace: name, Source: source, Quote: quote}}
+		}
+		if name != "" && p.isValue(0, ".") && p.isKind(1, Identifier) {
+			// exports.a = require('./module').b
+			property := p.tokens[p.index+1].Value
+			p.index += 2
+			if p.isExpressionEnd() {
+				return []Export{{Specifiers: []Specifier{{Name: property, Alias: name}}, Source: source, Quote: quote}}
+			}
+		}
+		p.index = start
+	}
+
+	switch {
+	case name == "" && p.isValue(0, "{"):
+		return p.parseExportedProperties()
+	case name != "" && p.isKind(0, Identifier) && !p.isValue(0, "function") && !p.isValue(0, "class"):
+		p.index++
+		if p.isExpressionEnd() {
+			// exports.a = b
+			return []Export{{Specifiers: []Specifier{{Name: p.tokens[p.index-1].Value, Alias: name}}}}
+		}
+		p.index = start
+	}
+
+	p.skipExpression(",", ";")
+	if name == "" {
+		return nil
+	}
+	return []Export{{Declarations: []string{name}}}
+}
+
+// parseExportedProperties parses the `{ ...require('./a'), b, c: require('./c') }`
+// object literal merged into the exports.
+func (p *statementParser) parseExportedProperties() []Export {
+	exports := []Export{}
+	p.index++
+	for p.index < len(p.tokens) && !p.isValue(0, "}") {
+		switch {
+		case p.isValue(0, ","):
+			p.index++
+			continue
+		case p.isValue(0, "..."):
+			p.index++
+			exports = append(exports, p.parseExportedValue("")...)
+		case p.isKind(0, Identifier) || p.isKind(0, String) || p.isKind(0, Number):
+			name, _ := unquote(p.tokens[p.index].Value)
+			p.index++
+			if (name == "get" || name == "set" || name == "async") && (p.isKind(0, Identifier) || p.isKind(0, String)) {
+				// { get a() {} }
+				name, _ = unquote(p.tokens[p.index].Value)
+				p.index++
+			}
+			switch {
+			case p.isValue(0, ",") || p.isValue(0, "}"):
+				// { a } is { a: a }
+				exports = append(exports, Export{Specifiers: []Specifier{{Name: name, Alias: name}}})
+			case p.isValue(0, ":"):
+				p.index++
+				exports = append(exports, p.parseExportedValue(name)...)
+			default:
+				// Methods and accessors such as { a() {} }
+				exports = append(exports, Export{Declarations: []string{name}})
+				p.skipExpression(",", "}")
+			}
+		default:
+			// Computed keys are ignored
+			p.skipExpression(",", "}")
+		}
+		if !p.isValue(0, ",") && !p.isValue(0, "}") {
+			// Unexpected token, skip it to make progress
+			p.index++
+		}
+	}
+	p.index = min(p.index+1, len(p.tokens))
+	return exports
+}
+
+// isStatementEnd reports whether the current token ends a statement
+func (p *statementParser) isStatementEnd() bool {
+	return p.index >= len(p.tokens) || p.isValue(0, ";") || p.isValue(0, "}") || p.isAutomaticSemicolon()
+}
+
+// isExpressionEnd reports whether the current token ends an expression
+func (p *statementParser) isExpressionEnd() bool {
+	return p.index >= len(p.tokens) || p.isValue(0, ",") || p.isValue(0, ";") || p.isValue(0, ")") || p.isValue(0, "}") || p.isAutomaticSemicolon()
+}
@@ -0,0 +1,78 @@
+package tokenizer
+
+// DynamicImport is an `import('module')` expression. Declaration and
+// Specifiers are set for `const { a, b: c } = await import('module')`
+// declarations, in which case Start and End span the whole declaration.
+type DynamicImport struct {
+	Start       Position
+	End         Position
+	Source      string
+	Quote       string
+	Declaration string // `const`, `let` or `var`
+	Specifiers  []Specifier
+	Semicolon   bool
+}
+
+// parseDynamicImports lists the dynamic imports found at any depth
+func (p *statementParser) parseDynamicImports() []DynamicImport {
+	dynamicImports := []DynamicImport{}
+	p.index = 0
+	for p.index < len(p.tokens) {
+		if p.isKind(0, Identifier) && !p.isValue(-1, ".") {
+			switch p.tokens[p.index].Value {
+			case "const", "let", "var":
+				if statement, ok := p.parseDynamicImportDeclaration(); ok {
+					dynamicImports = append(dynamicImports, statement)
+					continue
+				}
+			case "import":
+				if statement, ok := p.parseDynamicImport(); ok {
+					dynamicImports = append(dynamicImports, statement)
+					continue
+				}
+			}
+		}
+		p.index++
+	}
+	return dynamicImports
+}
+
+// parseDynamicImport parses `import('module')` expressions with a string literal module
+func (p *statementParser) parseDynamicImport() (DynamicImport, bool) {
+	if !p.isValue(0, "import") || !p.isValue(1, "(") || !p.isKind(2, String) || !p.isValue(3, ")") {
+		return DynamicImport{}, false
+	}
+	statement := DynamicImport{Start: p.tokens[p.index].Start, End: p.tokens[p.index+3].End}
+	statement.Source, statement.Quote = unquote(p.tokens[p.index+2].Value)
+	p.index += 4
+	return statement, true
+}
+
+// parseDynamicImportDeclaration parses `const { a, b: c } = await import('module')`
+func (p *statementParser) parseDynamicImportDeclaration() (DynamicImport, bool) {
+	start := p.index
+	declaration := p.tokens[start].Value
+	p.index++
+	if !p.isValue(0, "{") {
+		p.index = start
+		return DynamicImport{}, false
+	}
+	specifiers, ok := p.parseRequireProperties()
+	if !ok || !p.isValue(0, "=") || !p.isValue(1, "await") {
+		p.index = start
+		return DynamicImport{}, false
+	}
+	p.index += 2
+
+	statement, ok := p.parseDynamicImport()
+	if !ok || !p.isStatementEnd() {
+		p.index = start
+		return DynamicImport{}, false
+	}
+	statement.Start = p.tokens[start].Start
+	statement.Declaration = declaration
+	statement.Specifiers = specifiers
+	statement.Semicolon = p.skipSemicolon()
+	statement.End = p.previousEnd()
+	return statement, true
+}
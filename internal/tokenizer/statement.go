@@ -0,0 +1,493 @@
+package tokenizer
+
+import "strings"
+
+// Specifier is a single binding of an import or export clause. For
+// `import { a as b }` and `export { a as b }`, Name is `a` and Alias is `b`.
+type Specifier struct {
+	Name   string
+	Alias  string
+	IsType bool
+}
+
+// Import is an import declaration such as `import Default, { a as b } from 'module'`,
+// or a CommonJS require such as `const { a: b } = require('module')`.
+type Import struct {
+	Start      Position
+	End        Position
+	IsType     bool
+	Default    string
+	Namespace  string
+	Specifiers []Specifier
+	HasNamed   bool // whether the declaration contains a `{ ... }` clause
+	Source     string
+	Quote      string
+	Semicolon  bool
+	Require    string // `const`, `let` or `var` declaring a CommonJS require
+}
+
+// Export is an export declaration, either re-exporting from another module
+// (`export * from 'module'`, `export { a } from 'module'`) or exporting local
+// bindings (`export { a }`, `export const a = 1`). CommonJS exports such as
+// `module.exports = { ...require('module') }` are described the same way.
+type Export struct {
+	Start        Position
+	End          Position
+	IsType       bool
+	IsDefault    bool
+	IsStar       bool
+	Namespace    string // `ns` in `export * as ns from 'module'`
+	Specifiers   []Specifier
+	Declarations []string // names declared by `export const a`, `export class A`...
+	Source       string
+	Quote        string
+	Semicolon    bool
+}
+
+type Module struct {
+	Tokens         []Token
+	Imports        []Import
+	Exports        []Export
+	DynamicImports []DynamicImport
+}
+
+// Parse tokenizes source and extracts its top-level import and export
+// declarations, along with CommonJS requires and exports, and the dynamic
+// imports found at any depth.
+func Parse(source string, jsx bool) Module {
+	p := &statementParser{tokens: Tokenize(source, jsx)}
+	module := Module{Tokens: p.tokens}
+	depth := 0
+	for p.index < len(p.tokens) {
+		token := p.tokens[p.index]
+		isStatementStart := depth == 0 && token.Kind == Identifier && !p.isValue(-1, ".")
+		switch {
+		case token.Kind == Punctuator && token.Value == "{":
+			depth++
+		case token.Kind == Punctuator && token.Value == "}":
+			depth = max(depth-1, 0)
+		case isStatementStart && token.Value == "import":
+			if statement, ok := p.parseImport(); ok {
+				module.Imports = append(module.Imports, statement)
+				continue
+			}
+		case isStatementStart && token.Value == "export":
+			if statement, ok := p.parseExport(); ok {
+				module.Exports = append(module.Exports, statement)
+				continue
+			}
+		case isStatementStart && (token.Value == "const" || token.Value == "let" || token.Value == "var"):
+			if statement, ok := p.parseRequire(); ok {
+				module.Imports = append(module.Imports, statement)
+				continue
+			}
+		case isStatementStart && (token.Value == "module" || token.Value == "exports" || token.Value == "Object"):
+			if statements, ok := p.parseCommonJSExports(); ok {
+				module.Exports = append(module.Exports, statements...)
+				continue
+			}
+		}
+		p.index++
+	}
+	module.DynamicImports = p.parseDynamicImports()
+	return module
+}
+
+type statementParser struct {
+	tokens []Token
+	index  int
+}
+
+func (p *statementParser) parseImport() (Import, bool) {
+	start := p.index
+	statement := Import{Start: p.tokens[start].Start}
+	p.index++
+
+	if p.isValue(0, "type") && !p.isValue(1, ",") && !(p.isValue(1, "from") && p.isKind(2, String)) {
+		statement.IsType = true
+		p.index++
+	}
+	if p.isKind(0, Identifier) && !p.isValue(0, "from") || p.isValue(0, "from") && p.isValue(1, "from") {
+		statement.Default = p.tokens[p.index].Value
+		p.index++
+		if p.isValue(0, ",") {
+			p.index++
+		}
+	}
+	if p.isValue(0, "*") && p.isValue(1, "as") && p.isKind(2, Identifier) {
+		statement.Namespace = p.tokens[p.index+2].Value
+		p.index += 3
+	} else if p.isValue(0, "{") {
+		statement.HasNamed = true
+		statement.Specifiers = p.parseSpecifiers()
+	}
+
+	hasClause := statement.Default != "" || statement.Namespace != "" || statement.HasNamed
+	if hasClause {
+		if !p.isValue(0, "from") {
+			p.index = start
+			return Import{}, false
+		}
+		p.index++
+	}
+	if !p.isKind(0, String) {
+		p.index = start
+		return Import{}, false
+	}
+	statement.Source, statement.Quote = unquote(p.tokens[p.index].Value)
+	p.index++
+	p.skipImportAttributes()
+	statement.Semicolon = p.skipSemicolon()
+	statement.End = p.previousEnd()
+	return statement, true
+}
+
+func (p *statementParser) parseExport() (Export, bool) {
+	start := p.index
+	statement := Export{Start: p.tokens[start].Start}
+	p.index++
+
+	if p.isValue(0, "type") && (p.isValue(1, "{") || p.isValue(1, "*")) {
+		statement.IsType = true
+		p.index++
+	}
+
+	switch {
+	case p.isValue(0, "*"):
+		statement.IsStar = true
+		p.index++
+		if p.isValue(0, "as") && (p.isKind(1, Identifier) || p.isKind(1, String)) {
+			statement.Namespace, _ = unquote(p.tokens[p.index+1].Value)
+			p.index += 2
+		}
+		if !p.isValue(0, "from") || !p.isKind(1, String) {
+			p.index = start
+			return Export{}, false
+		}
+		statement.Source, statement.Quote = unquote(p.tokens[p.index+1].Value)
+		p.index += 2
+		p.skipImportAttributes()
+		statement.Semicolon = p.skipSemicolon()
+	case p.isValue(0, "{"):
+		statement.Specifiers = p.parseSpecifiers()
+		if p.isValue(0, "from") && p.isKind(1, String) {
+			statement.Source, statement.Quote = unquote(p.tokens[p.index+1].Value)
+			p.index += 2
+			p.skipImportAttributes()
+		}
+		statement.Semicolon = p.skipSemicolon()
+	case p.isValue(0, "default"):
+		statement.IsDefault = true
+		p.index++
+		if names, ok := p.parseDeclaration(); ok {
+			statement.Declarations = names
+		}
+	default:
+		names, ok := p.parseDeclaration()
+		if !ok {
+			p.index = start
+			return Export{}, false
+		}
+		statement.Declarations = names
+	}
+
+	statement.End = p.previousEnd()
+	return statement, true
+}
+
+// parseSpecifiers parses `{ a, b as c, type d }` clauses of imports and exports.
+func (p *statementParser) parseSpecifiers() []Specifier {
+	specifiers := []Specifier{}
+	p.index++
+	for p.index < len(p.tokens) && !p.isValue(0, "}") {
+		if p.isValue(0, ",") {
+			p.index++
+			continue
+		}
+
+		specifier := Specifier{}
+		if p.isValue(0, "type") && (p.isKind(1, Identifier) || p.isKind(1, String)) && !p.isValue(1, "as") {
+			specifier.IsType = true
+			p.index++
+		}
+		if !p.isKind(0, Identifier) && !p.isKind(0, String) {
+			p.index++
+			continue
+		}
+		specifier.Name, _ = unquote(p.tokens[p.index].Value)
+		specifier.Alias = specifier.Name
+		p.index++
+		if p.isValue(0, "as") && (p.isKind(1, Identifier) || p.isKind(1, String)) {
+			specifier.Alias, _ = unquote(p.tokens[p.index+1].Value)
+			p.index += 2
+		}
+		specifiers = append(specifiers, specifier)
+	}
+	p.index = min(p.index+1, len(p.tokens))
+	return specifiers
+}
+
+// skipImportAttributes skips `with { type: 'json' }` or the legacy `assert { type: 'json' }`
+func (p *statementParser) skipImportAttributes() {
+	if (!p.isValue(0, "with") && !p.isValue(0, "assert")) || !p.isValue(1, "{") {
+		return
+	}
+	for p.index < len(p.tokens) && !p.isValue(0, "}") {
+		p.index++
+	}
+	p.index = min(p.index+1, len(p.tokens))
+}
+
+func (p *statementParser) skipSemicolon() bool {
+	if p.isValue(0, ";") {
+		p.index++
+		return true
+	}
+	return false
+}
+
+func (p *statementParser) previousEnd() Position {
+	return p.tokens[min(p.index, len(p.tokens))-1].End
+}
+
+// parseDeclaration parses the declaration following `export` and returns the
+// names it declares, such as `a` and `b` for `export const { a, b } = c`.
+func (p *statementParser) parseDeclaration() ([]string, bool) {
+	for p.isValue(0, "declare") || p.isValue(0, "async") || p.isValue(0, "abstract") {
+		p.index++
+	}
+	if !p.isKind(0, Identifier) {
+		return nil, false
+	}
+
+	switch p.tokens[p.index].Value {
+	case "const":
+		p.index++
+		if p.isValue(0, "enum") {
+			p.index++
+			return p.parseDeclarationName()
+		}
+		return p.parseVariableDeclarators()
+	case "let", "var":
+		p.index++
+		return p.parseVariableDeclarators()
+	case "function":
+		p.index++
+		if p.isValue(0, "*") {
+			p.index++
+		}
+		return p.parseDeclarationName()
+	case "class", "interface", "enum", "type", "namespace", "module", "import":
+		p.index++
+		if p.isKind(0, String) {
+			// declare module 'module' { ... } doesn't declare any local name
+			p.index++
+			return []string{}, true
+		}
+		return p.parseDeclarationName()
+	}
+	return nil, false
+}
+
+func (p *statementParser) parseDeclarationName() ([]string, bool) {
+	if !p.isKind(0, Identifier) {
+		return []string{}, true
+	}
+	p.index++
+	return []string{p.tokens[p.index-1].Value}, true
+}
+
+// parseVariableDeclarators parses `a = 1, { b, c: [d] } = e` declarators
+func (p *statementParser) parseVariableDeclarators() ([]string, bool) {
+	names := []string{}
+	for p.index < len(p.tokens) {
+		names = append(names, p.parseBindingPattern()...)
+		p.skipDeclaratorInitializer()
+		if !p.isValue(0, ",") {
+			break
+		}
+		p.index++
+	}
+	p.skipSemicolon()
+	return names, len(names) > 0
+}
+
+// parseBindingPattern returns the names bound by an identifier, an object
+// pattern or an array pattern, skipping default values.
+func (p *statementParser) parseBindingPattern() []string {
+	switch {
+	case p.isKind(0, Identifier):
+		p.index++
+		return []string{p.tokens[p.index-1].Value}
+	case p.isValue(0, "{"), p.isValue(0, "["):
+		closing := "}"
+		if p.isValue(0, "[") {
+			closing = "]"
+		}
+		p.index++
+		names := []string{}
+		for p.index < len(p.tokens) && !p.isValue(0, closing) {
+			switch {
+			case p.isValue(0, ","):
+				p.index++
+				continue
+			case p.isValue(0, "..."):
+				p.index++
+				names = append(names, p.parseBindingPattern()...)
+			case closing == "}" && p.isValue(0, "["):
+				// { [computed]: pattern }
+				p.skipBrackets()
+				p.index++
+				names = append(names, p.parseBindingPattern()...)
+			case closing == "}" && p.isValue(1, ":"):
+				p.index += 2
+				names = append(names, p.parseBindingPattern()...)
+			default:
+				names = append(names, p.parseBindingPattern()...)
+			}
+			if p.isValue(0, "=") {
+				p.index++
+				p.skipExpression(",", closing)
+			}
+			if !p.isValue(0, ",") && !p.isValue(0, closing) {
+				// Unexpected token, skip it to make progress
+				p.index++
+			}
+		}
+		p.index = min(p.index+1, len(p.tokens))
+		return names
+	}
+	p.index++
+	return nil
+}
+
+// skipDeclaratorInitializer skips the type annotation and the initializer of a
+// variable declarator, up to the next declarator or the end of the statement.
+func (p *statementParser) skipDeclaratorInitializer() {
+	if p.isValue(0, ":") {
+		p.index++
+		angleDepth := 0
+		for p.index < len(p.tokens) && !p.isAutomaticSemicolon() {
+			if angleDepth == 0 && (p.isValue(0, "=") || p.isValue(0, ",") || p.isValue(0, ";")) {
+				break
+			}
+			switch {
+			case p.isValue(0, "<"):
+				angleDepth++
+			case p.isValue(0, ">"):
+				angleDepth--
+			case p.isValue(0, ">>"):
+				angleDepth -= 2
+			case p.isValue(0, "("), p.isValue(0, "["), p.isValue(0, "{"):
+				p.skipBrackets()
+				continue
+			}
+			p.index++
+		}
+	}
+	if p.isValue(0, "=") {
+		p.index++
+		p.skipExpression(",", ";")
+	}
+}
+
+// skipExpression skips tokens until one of stops is found outside of any
+// brackets, an unbalanced closing bracket is found, or the statement ends.
+func (p *statementParser) skipExpression(stops ...string) {
+	start := p.index
+	for p.index < len(p.tokens) {
+		if p.index > start && p.isAutomaticSemicolon() {
+			return
+		}
+		for _, stop := range stops {
+			if p.isValue(0, stop) {
+				return
+			}
+		}
+		if p.isValue(0, ")") || p.isValue(0, "]") || p.isValue(0, "}") {
+			return
+		}
+		if p.isValue(0, "(") || p.isValue(0, "[") || p.isValue(0, "{") {
+			p.skipBrackets()
+			continue
+		}
+		p.index++
+	}
+}
+
+// skipBrackets skips balanced brackets starting at the current opening bracket
+func (p *statementParser) skipBrackets() {
+	depth := 0
+	for p.index < len(p.tokens) {
+		if p.isValue(0, "(") || p.isValue(0, "[") || p.isValue(0, "{") {
+			depth++
+		} else if p.isValue(0, ")") || p.isValue(0, "]") || p.isValue(0, "}") {
+			depth--
+		}
+		p.index++
+		if depth == 0 {
+			return
+		}
+	}
+}
+
+// isAutomaticSemicolon reports whether a line break before the current token
+// ends the statement, following automatic semicolon insertion.
+func (p *statementParser) isAutomaticSemicolon() bool {
+	if p.index == 0 || p.index >= len(p.tokens) {
+		return p.index >= len(p.tokens)
+	}
+	previous, current := p.tokens[p.index-1], p.tokens[p.index]
+	if current.Start.Line == previous.End.Line {
+		return false
+	}
+	if current.Kind == Punctuator {
+		return current.Value == "}"
+	}
+	if previous.Kind == Punctuator {
+		switch previous.Value {
+		case ")", "]", "}", ">", "++", "--":
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+func (p *statementParser) isKind(n int, kind Kind) bool {
+	index := p.index + n
+	return index >= 0 && index < len(p.tokens) && p.tokens[index].Kind == kind
+}
+
+func (p *statementParser) isValue(n int, value string) bool {
+	index := p.index + n
+	return index >= 0 && index < len(p.tokens) && p.tokens[index].Kind != String && p.tokens[index].Value == value
+}
+
+// StringValue returns the value of a string literal token, or the value of
+// any other token.
+func StringValue(token Token) string {
+	value, _ := unquote(token.Value)
+	return value
+}
+
+// unquote returns the value of a string literal token along with its quote
+func unquote(value string) (string, string) {
+	if value == "" || (value[0] != '\'' && value[0] != '"') {
+		return value, ""
+	}
+	quote := value[:1]
+	value = strings.TrimSuffix(value[1:], quote)
+	if !strings.Contains(value, `\`) {
+		return value, quote
+	}
+
+	var unescaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+		}
+		unescaped.WriteByte(value[i])
+	}
+	return unescaped.String(), quote
+}
@@ -0,0 +1,543 @@
+package tokenizer
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Kind int
+
+const (
+	Identifier Kind = iota
+	Punctuator
+	String
+	Template
+	Number
+	RegExp
+)
+
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number in characters, starting at 1
+}
+
+type Token struct {
+	Kind  Kind
+	Value string
+	Start Position
+	End   Position
+}
+
+var (
+	// Longest punctuators first so that `>>>=` is not read as `>`, `>`, `>=`
+	punctuators = []string{
+		">>>=",
+		"...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+		"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<", ">>", "**",
+	}
+	// Keywords after which a `/` starts a regular expression instead of a division
+	keywordsBeforeExpression = map[string]struct{}{
+		"await": {}, "case": {}, "delete": {}, "do": {}, "else": {}, "in": {}, "instanceof": {},
+		"new": {}, "return": {}, "throw": {}, "typeof": {}, "void": {}, "yield": {},
+	}
+)
+
+type scanner struct {
+	source     string
+	jsx        bool
+	offset     int
+	lineStarts []int
+	tokens     []Token
+	exprEnd    bool // whether the previous token can end an expression, in which case `/` is a division
+}
+
+// Tokenize splits a JavaScript or TypeScript source into tokens, skipping
+// whitespaces and comments. JSX elements are only recognised when jsx is set.
+func Tokenize(source string, jsx bool) []Token {
+	s := &scanner{
+		source:     source,
+		jsx:        jsx,
+		lineStarts: getLineStarts(source),
+	}
+	s.skipHashbang()
+	s.scanTokens(false)
+	return s.tokens
+}
+
+// SupportsJSX reports whether the file at path may contain JSX elements.
+// TypeScript files are excluded as `<Type>value` is a type assertion there.
+func SupportsJSX(path string) bool {
+	switch filepath.Ext(path) {
+	case ".ts", ".mts", ".cts":
+		return false
+	}
+	return true
+}
+
+func (s *scanner) scanTokens(untilClosingBrace bool) {
+	depth := 0
+	for {
+		s.skipTrivia()
+		if s.offset >= len(s.source) {
+			return
+		}
+
+		c := s.source[s.offset]
+		switch {
+		case c == '}' && untilClosingBrace && depth == 0:
+			return
+		case c == '{':
+			depth++
+			s.scanPunctuator()
+		case c == '}':
+			depth--
+			s.scanPunctuator()
+		case c == '\'' || c == '"':
+			s.scanString(c)
+		case c == '`':
+			s.scanTemplate()
+		case isDigit(c) || (c == '.' && isDigit(s.peek(1))):
+			s.scanNumber()
+		case c == '/' && !s.exprEnd:
+			s.scanRegExp()
+		case c == '<' && s.jsx && !s.exprEnd && s.isJSXElementStart():
+			s.scanJSXElement()
+		case c == '#' && s.isIdentifierStartAt(s.offset+1):
+			start := s.offset
+			s.offset++
+			s.scanIdentifier(start)
+		case s.isIdentifierStartAt(s.offset):
+			s.scanIdentifier(s.offset)
+		default:
+			s.scanPunctuator()
+		}
+	}
+}
+
+func (s *scanner) skipHashbang() {
+	if strings.HasPrefix(s.source, "#!") {
+		s.skipLine()
+	}
+}
+
+func (s *scanner) skipTrivia() {
+	for s.offset < len(s.source) {
+		c := s.source[s.offset]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f':
+			s.offset++
+		case c == '/' && s.peek(1) == '/':
+			s.skipLine()
+		case c == '/' && s.peek(1) == '*':
+			end := strings.Index(s.source[s.offset+2:], "*/")
+			if end < 0 {
+				s.offset = len(s.source)
+			} else {
+				s.offset += end + 4
+			}
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(s.source[s.offset:])
+			if !unicode.IsSpace(r) && r != '\uFEFF' {
+				return
+			}
+			s.offset += size
+		default:
+			return
+		}
+	}
+}
+
+func (s *scanner) skipLine() {
+	end := strings.IndexAny(s.source[s.offset:], "\r\n")
+	if end < 0 {
+		s.offset = len(s.source)
+	} else {
+		s.offset += end
+	}
+}
+
+func (s *scanner) scanString(quote byte) {
+	start := s.offset
+	s.offset++
+	for s.offset < len(s.source) {
+		c := s.source[s.offset]
+		if c == '\\' {
+			s.offset += 2
+			continue
+		}
+		if c == '\n' || c == '\r' {
+			break
+		}
+		s.offset++
+		if c == quote {
+			break
+		}
+	}
+	s.emit(String, start)
+	s.exprEnd = true
+}
+
+func (s *scanner) scanTemplate() {
+	start := s.offset
+	s.offset++
+	for s.offset < len(s.source) {
+		switch s.source[s.offset] {
+		case '\\':
+			s.offset += 2
+		case '`':
+			s.offset++
+			s.emit(Template, start)
+			s.exprEnd = true
+			return
+		case '$':
+			s.offset++
+			if s.peek(0) != '{' {
+				continue
+			}
+			s.offset++
+			s.emit(Template, start)
+			s.exprEnd = false
+			s.scanTokens(true)
+			start = s.offset
+			if s.offset < len(s.source) {
+				s.offset++
+			}
+		default:
+			s.offset++
+		}
+	}
+	s.emit(Template, start)
+	s.exprEnd = true
+}
+
+func (s *scanner) scanNumber() {
+	start := s.offset
+	isHex := strings.HasPrefix(s.source[s.offset:], "0x") || strings.HasPrefix(s.source[s.offset:], "0X")
+	for s.offset < len(s.source) {
+		c := s.source[s.offset]
+		isExponentSign := (c == '+' || c == '-') && !isHex && (s.source[s.offset-1] == 'e' || s.source[s.offset-1] == 'E')
+		if !isDigit(c) && !isASCIILetter(c) && c != '_' && c != '.' && !isExponentSign {
+			break
+		}
+		s.offset++
+	}
+	s.emit(Number, start)
+	s.exprEnd = true
+}
+
+func (s *scanner) scanRegExp() {
+	start := s.offset
+	s.offset++
+	inClass := false
+	for s.offset < len(s.source) {
+		c := s.source[s.offset]
+		switch {
+		case c == '\\':
+			s.offset += 2
+			continue
+		case c == '\n' || c == '\r':
+			// Not a regular expression after all, most likely a division
+			s.offset = start
+			s.scanPunctuator()
+			return
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			s.offset++
+			for s.offset < len(s.source) && isASCIILetter(s.source[s.offset]) {
+				s.offset++
+			}
+			s.emit(RegExp, start)
+			s.exprEnd = true
+			return
+		}
+		s.offset++
+	}
+	s.emit(RegExp, start)
+	s.exprEnd = true
+}
+
+func (s *scanner) scanIdentifier(start int) {
+	s.skipIdentifierParts()
+	s.emit(Identifier, start)
+	_, isKeyword := keywordsBeforeExpression[s.source[start:s.offset]]
+	s.exprEnd = !isKeyword
+}
+
+func (s *scanner) skipIdentifierParts() {
+	for s.offset < len(s.source) {
+		if strings.HasPrefix(s.source[s.offset:], `\u`) {
+			s.skipUnicodeEscape()
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s.source[s.offset:])
+		if !isIdentifierPart(r) {
+			return
+		}
+		s.offset += size
+	}
+}
+
+// skipUnicodeEscape skips `\uXXXX` or `\u{X...}` escape sequences found in identifiers
+func (s *scanner) skipUnicodeEscape() {
+	s.offset += 2
+	if s.peek(0) == '{' {
+		if end := strings.IndexByte(s.source[s.offset:], '}'); end >= 0 {
+			s.offset += end + 1
+		}
+		return
+	}
+	s.offset = min(s.offset+4, len(s.source))
+}
+
+func (s *scanner) scanPunctuator() {
+	start := s.offset
+	for _, punctuator := range punctuators {
+		if strings.HasPrefix(s.source[s.offset:], punctuator) {
+			if punctuator == "?." && isDigit(s.peek(2)) {
+				continue
+			}
+			s.offset += len(punctuator)
+			break
+		}
+	}
+	if s.offset == start {
+		_, size := utf8.DecodeRuneInString(s.source[s.offset:])
+		s.offset += size
+	}
+	s.emit(Punctuator, start)
+
+	switch s.source[start:s.offset] {
+	case ")", "]", "}":
+		s.exprEnd = true
+	case "++", "--":
+	default:
+		s.exprEnd = false
+	}
+}
+
+// isJSXElementStart tells apart `<div>` from a generic arrow function `<T,>() => {}`
+func (s *scanner) isJSXElementStart() bool {
+	next := s.offset + 1
+	if next < len(s.source) && s.source[next] == '>' {
+		return true
+	}
+	if !s.isIdentifierStartAt(next) {
+		return false
+	}
+
+	end := next
+	for end < len(s.source) {
+		r, size := utf8.DecodeRuneInString(s.source[end:])
+		if !isIdentifierPart(r) && r != '-' {
+			break
+		}
+		end += size
+	}
+	rest := strings.TrimLeft(s.source[end:], " \t\r\n")
+	return !strings.HasPrefix(rest, ",") && !strings.HasPrefix(rest, "extends ")
+}
+
+func (s *scanner) scanJSXElement() {
+	s.emitBytes(Punctuator, 1)
+	s.skipTrivia()
+	if s.peek(0) == '>' {
+		s.emitBytes(Punctuator, 1)
+		s.scanJSXChildren()
+		return
+	}
+
+	s.scanJSXName()
+	if !s.scanJSXAttributes() {
+		s.scanJSXChildren()
+	}
+	s.exprEnd = true
+}
+
+func (s *scanner) scanJSXName() {
+	for {
+		s.skipTrivia()
+		if !s.scanJSXIdentifier() {
+			return
+		}
+		s.skipTrivia()
+		if c := s.peek(0); c != '.' && c != ':' {
+			return
+		}
+		s.emitBytes(Punctuator, 1)
+	}
+}
+
+func (s *scanner) scanJSXIdentifier() bool {
+	start := s.offset
+	for s.offset < len(s.source) {
+		r, size := utf8.DecodeRuneInString(s.source[s.offset:])
+		if !isIdentifierPart(r) && r != '-' {
+			break
+		}
+		s.offset += size
+	}
+	if s.offset == start {
+		return false
+	}
+	s.emit(Identifier, start)
+	return true
+}
+
+// scanJSXAttributes scans attributes until the end of the opening tag and
+// reports whether the element is self-closing.
+func (s *scanner) scanJSXAttributes() bool {
+	for {
+		s.skipTrivia()
+		if s.offset >= len(s.source) {
+			return true
+		}
+		switch c := s.source[s.offset]; c {
+		case '/':
+			s.emitBytes(Punctuator, 1)
+			s.skipTrivia()
+			if s.peek(0) == '>' {
+				s.emitBytes(Punctuator, 1)
+			}
+			return true
+		case '>':
+			s.emitBytes(Punctuator, 1)
+			return false
+		case '{':
+			s.scanJSXExpression()
+		case '<':
+			s.scanJSXElement()
+		case '"', '\'':
+			start := s.offset
+			end := strings.IndexByte(s.source[s.offset+1:], c)
+			if end < 0 {
+				s.offset = len(s.source)
+			} else {
+				s.offset += end + 2
+			}
+			s.emit(String, start)
+		default:
+			if !s.scanJSXIdentifier() {
+				s.scanPunctuator()
+			}
+		}
+	}
+}
+
+func (s *scanner) scanJSXChildren() {
+	for s.offset < len(s.source) {
+		switch s.source[s.offset] {
+		case '{':
+			s.scanJSXExpression()
+		case '<':
+			if !strings.HasPrefix(strings.TrimLeft(s.source[s.offset+1:], " \t\r\n"), "/") {
+				s.scanJSXElement()
+				continue
+			}
+			s.emitBytes(Punctuator, 1)
+			s.skipTrivia()
+			s.emitBytes(Punctuator, 1)
+			s.scanJSXName()
+			s.skipTrivia()
+			if s.peek(0) == '>' {
+				s.emitBytes(Punctuator, 1)
+			}
+			return
+		default:
+			s.offset++
+		}
+	}
+}
+
+func (s *scanner) scanJSXExpression() {
+	s.emitBytes(Punctuator, 1)
+	s.exprEnd = false
+	s.scanTokens(true)
+	if s.offset < len(s.source) {
+		s.emitBytes(Punctuator, 1)
+	}
+}
+
+func (s *scanner) emitBytes(kind Kind, size int) {
+	start := s.offset
+	s.offset = min(s.offset+size, len(s.source))
+	s.emit(kind, start)
+}
+
+func (s *scanner) emit(kind Kind, start int) {
+	s.offset = min(s.offset, len(s.source))
+	s.tokens = append(s.tokens, Token{
+		Kind:  kind,
+		Value: s.source[start:s.offset],
+		Start: s.position(start),
+		End:   s.position(s.offset),
+	})
+}
+
+func (s *scanner) position(offset int) Position {
+	line := sort.Search(len(s.lineStarts), func(i int) bool { return s.lineStarts[i] > offset }) - 1
+	lineStart := s.lineStarts[line]
+	return Position{
+		Offset: offset,
+		Line:   line + 1,
+		Column: utf8.RuneCountInString(s.source[lineStart:offset]) + 1,
+	}
+}
+
+func (s *scanner) peek(n int) byte {
+	if s.offset+n >= len(s.source) {
+		return 0
+	}
+	return s.source[s.offset+n]
+}
+
+func (s *scanner) isIdentifierStartAt(offset int) bool {
+	if offset >= len(s.source) {
+		return false
+	}
+	if s.source[offset] == '\\' {
+		return strings.HasPrefix(s.source[offset:], `\u`)
+	}
+	r, _ := utf8.DecodeRuneInString(s.source[offset:])
+	return isIdentifierStart(r)
+}
+
+func getLineStarts(source string) []int {
+	lineStarts := []int{0}
+	for i := 0; i < len(source); i++ {
+		switch source[i] {
+		case '\r':
+			if i+1 < len(source) && source[i+1] == '\n' {
+				i++
+			}
+			lineStarts = append(lineStarts, i+1)
+		case '\n':
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	return lineStarts
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r) || unicode.Is(unicode.Nl, r) || unicode.Is(unicode.Other_ID_Start, r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) ||
+		unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) ||
+		r == '\u200C' || r == '\u200D'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
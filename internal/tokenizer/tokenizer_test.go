@@ -0,0 +1,137 @@
+package tokenizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeSkipsCommentsAndStrings(t *testing.T) {
+	source := "// export * from './a'\nconst a = 'export * from \"./b\"' /* export * from './c' */\nconst b = `${a} export * from './d'`\n"
+	module := Parse(source, false)
+	assert.Empty(t, module.Exports)
+	assert.Empty(t, module.Imports)
+}
+
+func TestTokenizeRegExpAndDivision(t *testing.T) {
+	tokens := Tokenize("const a = b / c / d; const re = /'export'/g;", false)
+	kinds := []Kind{}
+	for _, token := range tokens {
+		kinds = append(kinds, token.Kind)
+	}
+	assert.Equal(t, []Kind{
+		Identifier, Identifier, Punctuator, Identifier, Punctuator, Identifier, Punctuator, Identifier, Punctuator,
+		Identifier, Identifier, Punctuator, RegExp, Punctuator,
+	}, kinds)
+}
+
+func TestTokenizeJSX(t *testing.T) {
+	source := "const a = <p title='it\"s'>Don't {value}</p>;\nimport { b } from './b';"
+	module := Parse(source, true)
+	assert.Len(t, module.Imports, 1)
+	assert.Equal(t, "./b", module.Imports[0].Source)
+}
+
+func TestParsePositions(t *testing.T) {
+	source := "const café = 1;\n  import type\n  { A, B as C, type D }\n  from './module';\nexport { café as E } from \"./e\"\n"
+	module := Parse(source, false)
+	assert.Len(t, module.Imports, 1)
+	statement := module.Imports[0]
+	assert.Equal(t, Position{Offset: 19, Line: 2, Column: 3}, statement.Start)
+	assert.Equal(t, Position{Offset: 73, Line: 4, Column: 19}, statement.End)
+	assert.True(t, statement.IsType)
+	assert.True(t, statement.Semicolon)
+	assert.Equal(t, "./module", statement.Source)
+	assert.Equal(t, "'", statement.Quote)
+	assert.Equal(t, []Specifier{
+		{Name: "A", Alias: "A"},
+		{Name: "B", Alias: "C"},
+		{Name: "D", Alias: "D", IsType: true},
+	}, statement.Specifiers)
+
+	assert.Len(t, module.Exports, 1)
+	assert.Equal(t, Position{Offset: 74, Line: 5, Column: 1}, module.Exports[0].Start)
+	assert.Equal(t, []Specifier{{Name: "café", Alias: "E"}}, module.Exports[0].Specifiers)
+	assert.Equal(t, "./e", module.Exports[0].Source)
+}
+
+func TestParseDeclarations(t *testing.T) {
+	source := `export declare async function a(): Promise<void>;
+export const b = () => <div>{value}</div>
+export let { c, d: [e, ...f], g = h(1, 2), ...i } = j, k: Record<string, number> = {}
+export default abstract class L {}
+export declare module "m" {
+  export const ignored = 1;
+}
+export { m, n as o }
+`
+	module := Parse(source, true)
+	declarations := [][]string{}
+	for _, export := range module.Exports {
+		declarations = append(declarations, export.Declarations)
+	}
+	assert.Equal(t, [][]string{{"a"}, {"b"}, {"c", "e", "f", "g", "i", "k"}, {"L"}, {}, nil}, declarations)
+	assert.True(t, module.Exports[3].IsDefault)
+	assert.Equal(t, []Specifier{{Name: "m", Alias: "m"}, {Name: "n", Alias: "o"}}, module.Exports[5].Specifiers)
+}
+
+func TestParseCommonJS(t *testing.T) {
+	source := `const { a, b: c } = require("./a");
+const d = require('./d')
+let { e = 1 } = require("./e");
+module.exports = { ...require("./f"), g: require("./g"), h: require("./h").i, a, j: 1, k() {} };
+exports.l = require("./l").m;
+Object.assign(module.exports, require("./n"));
+module.exports = function () {};
+`
+	module := Parse(source, false)
+	assert.Len(t, module.Imports, 2)
+	assert.Equal(t, "const", module.Imports[0].Require)
+	assert.Equal(t, []Specifier{{Name: "a", Alias: "a"}, {Name: "b", Alias: "c"}}, module.Imports[0].Specifiers)
+	assert.True(t, module.Imports[0].Semicolon)
+	assert.Equal(t, "d", module.Imports[1].Namespace)
+	assert.Equal(t, "'", module.Imports[1].Quote)
+	assert.False(t, module.Imports[1].Semicolon)
+
+	exports := []Export{}
+	for _, export := range module.Exports {
+		exports = append(exports, Export{
+			IsStar:       export.IsStar,
+			Namespace:    export.Namespace,
+			Specifiers:   export.Specifiers,
+			Declarations: export.Declarations,
+			Source:       export.Source,
+		})
+	}
+	assert.Equal(t, []Export{
+		{IsStar: true, Source: "./f"},
+		{IsStar: true, Namespace: "g", Source: "./g"},
+		{Specifiers: []Specifier{{Name: "i", Alias: "h"}}, Source: "./h"},
+		{Specifiers: []Specifier{{Name: "a", Alias: "a"}}},
+		{Declarations: []string{"j"}},
+		{Declarations: []string{"k"}},
+		{Specifiers: []Specifier{{Name: "m", Alias: "l"}}, Source: "./l"},
+		{IsStar: true, Source: "./n"},
+	}, exports)
+}
+
+func TestParseDynamicImports(t *testing.T) {
+	source := `export const load = async () => {
+  const { a, b: c } = await import("./a");
+  let { d = 1 } = await import("./d");
+  return import('./e').then((module) => module.e);
+};
+obj.import("./f");
+`
+	module := Parse(source, false)
+	sources := []string{}
+	for _, statement := range module.DynamicImports {
+		sources = append(sources, statement.Source)
+	}
+	assert.Equal(t, []string{"./a", "./d", "./e"}, sources)
+	assert.Equal(t, "const", module.DynamicImports[0].Declaration)
+	assert.Equal(t, []Specifier{{Name: "a", Alias: "a"}, {Name: "b", Alias: "c"}}, module.DynamicImports[0].Specifiers)
+	assert.Equal(t, Position{Offset: 36, Line: 2, Column: 3}, module.DynamicImports[0].Start)
+	assert.Empty(t, module.DynamicImports[1].Declaration)
+	assert.Equal(t, 4, module.DynamicImports[2].Start.Line)
+}
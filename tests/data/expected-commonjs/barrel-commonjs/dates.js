@@ -0,0 +1,9 @@
+function parseDate(value) {
+  return new Date(value);
+}
+
+function formatDate(date) {
+  return date.toISOString();
+}
+
+module.exports = { parseDate, formatDate };
@@ -0,0 +1,12 @@
+const { formatDate } = require("./dates");
+
+module.exports = {
+  ...require("./strings"),
+  numbers: require("./numbers"),
+  parseDate: require("./dates").parseDate,
+  formatDate,
+};
+
+exports.VERSION = require("./version").VERSION;
+
+Object.assign(module.exports, require("./arrays"));
@@ -0,0 +1,6 @@
+module.exports = {
+  add(a, b) {
+    return a + b;
+  },
+  ZERO: 0,
+};
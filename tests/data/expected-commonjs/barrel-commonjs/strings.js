@@ -0,0 +1,4 @@
+exports.capitalize = (value) => value.charAt(0).toUpperCase() + value.slice(1);
+exports.lowercase = function (value) {
+  return value.toLowerCase();
+};
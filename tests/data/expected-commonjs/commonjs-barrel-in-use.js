@@ -0,0 +1,9 @@
+const { capitalize } = require("./barrel-commonjs/strings");
+const numbers = require("./barrel-commonjs/numbers");
+const { parseDate, formatDate: format } = require("./barrel-commonjs/dates");
+const { chunk } = require("./barrel-commonjs/arrays");
+const { VERSION } = require("./barrel-commonjs/version");
+const lib = require("./barrel-commonjs");
+let { lowercase } = require('./barrel-commonjs/strings')
+
+module.exports = { capitalize, numbers, parseDate, format, chunk, VERSION, lib, lowercase };
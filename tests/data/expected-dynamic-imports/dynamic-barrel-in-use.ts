@@ -0,0 +1,7 @@
+export async function loadChart() {
+  const [{ BASIC_CONST }, BasicConstants, { basicFunction: format }] = await Promise.all([import("@barrel-basic/constants"), import("@barrel-basic/constants"), import("@barrel-basic/functions")]);
+  const { Button: PrimaryButton } = await import('./barrel-nested/Buttons/button');
+  return { BASIC_CONST, BasicConstants, format, PrimaryButton };
+}
+
+export const lazyNested = () => import("@barrel-nested");
@@ -0,0 +1,17 @@
+import {
+  // Buttons
+  Button, // primary action
+  IconButton
+} from "./components/button";
+import {
+  Card /* deprecated */
+} from "./components/card";
+import {
+  Modal
+} from "./components/modal";
+import {Card as Tile} from "./components/card";
+
+export { Button as Action } from "./components/button";
+export { Modal as Dialog } from "./components/modal";
+
+export const App = () => [Button(), IconButton(), Card(), Modal(), Tile()];
@@ -0,0 +1,3 @@
+export * from "./button";
+export * from "./card";
+export { Modal } from "./modal";
@@ -0,0 +1,8 @@
+const {
+	Button,
+} = require("./components/button");
+const {
+	Card: Tile, // legacy name
+} = require("./components/card");
+
+module.exports = () => [Button(), Tile()];
@@ -0,0 +1,8 @@
+﻿import {
+  Button,
+} from "./components/button";
+import {
+  Card,
+} from "./components/card";
+
+export const Page = () => [Button(), Card()];
@@ -0,0 +1,3 @@
+import { LegacyButton } from "@ui/legacy-button";
+
+export const Page = () => LegacyButton();
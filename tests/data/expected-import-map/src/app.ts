@@ -0,0 +1,4 @@
+import { Button } from "@ui/button";
+import { Input } from "@ui/forms/input";
+
+export const App = () => [Button(), Input()];
@@ -0,0 +1 @@
+export const LegacyButton = () => "legacy button";
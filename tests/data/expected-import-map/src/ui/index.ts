@@ -0,0 +1,2 @@
+export * from "./button";
+export * from "./forms/input";
@@ -0,0 +1,4 @@
+import { Button } from "@/components/button";
+import { Card } from "@/components/card";
+
+export const Home = () => [Button(), Card()];
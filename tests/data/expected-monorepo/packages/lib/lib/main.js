@@ -0,0 +1,3 @@
+import { trim } from "@/utils/strings";
+
+export const main = () => trim(" main ");
@@ -0,0 +1,6 @@
+import { Button } from "./components/button/Button.js";
+import { Card } from "./components/card/index.js";
+import { legacy } from "./components/legacy.mjs";
+import type { Theme } from "./components/types.js";
+
+export const App = (theme: Theme) => [Button(), Card(), legacy(), theme];
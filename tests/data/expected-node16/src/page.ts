@@ -0,0 +1,5 @@
+import { Button } from "./components/button/Button.tsx";
+import { Card } from "./components/card/index.ts";
+import { legacy } from "./components/legacy.mjs";
+
+export const Page = () => [Button(), Card(), legacy()];
@@ -0,0 +1 @@
+export const Api = () => "api";
@@ -0,0 +1 @@
+export const getOrder = () => "order";
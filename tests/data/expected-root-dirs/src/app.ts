@@ -0,0 +1,5 @@
+import { getUser } from "./services/users";
+import { getOrder } from "./services/orders";
+import { Api } from "./api/client";
+
+export const App = () => [getUser(), getOrder(), Api()];
@@ -0,0 +1,5 @@
+import { Button } from "#ui/button";
+import { Icon } from "#ui/icons/icon";
+import { Card } from "./widgets/card";
+
+export const App = () => [Button(), Icon(), Card()];
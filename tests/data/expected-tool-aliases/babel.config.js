@@ -0,0 +1,16 @@
+module.exports = {
+  presets: ["module:metro-react-native-babel-preset"],
+  plugins: [
+    [
+      "module-resolver",
+      {
+        root: ["./src"],
+        alias: {
+          "@components": "./src/components",
+          "^@screens/(.+)": "./src/screens/\\1",
+          underscore: "lodash",
+        },
+      },
+    ],
+  ],
+};
@@ -0,0 +1,9 @@
+import { Button } from "@components/button";
+import { Card } from "@components/card";
+import { Header } from "@screens/home/header";
+import { useTheme } from "@hooks/use-theme";
+import { formatDate } from "@utils/format";
+import { fetchUser } from "./services/api";
+import { addToCart } from "@store/cart";
+
+export const App = () => [Button(), Card(), Header(), useTheme(), formatDate(new Date()), fetchUser(), addToCart(1)];
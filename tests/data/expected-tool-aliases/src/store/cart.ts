@@ -0,0 +1 @@
+export const addToCart = (item) => [item];
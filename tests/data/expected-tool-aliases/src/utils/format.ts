@@ -0,0 +1 @@
+export const formatDate = (date) => date.toISOString();
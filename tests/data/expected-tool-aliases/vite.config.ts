@@ -0,0 +1,13 @@
+import path from "path";
+import { defineConfig } from "vite";
+import react from "@vitejs/plugin-react";
+
+export default defineConfig({
+  plugins: [react()],
+  resolve: {
+    alias: [
+      { find: "@utils", replacement: path.resolve(__dirname, "./src/utils") },
+      { find: /^~(.*)$/, replacement: "$1" },
+    ],
+  },
+});
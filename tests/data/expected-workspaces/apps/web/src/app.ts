@@ -0,0 +1,6 @@
+import { Button } from "@acme/ui/button";
+import { Card } from "@acme/ui/components/card";
+import { theme, tokens } from "@acme/ui";
+import { format } from "@acme/utils/src/strings/format";
+
+export const App = () => [Button(), Card(), theme, tokens, format(1)];
@@ -0,0 +1,4 @@
+export * from "./button";
+export * from "./theme";
+export * from "./components/card";
+export * from "./components/internal/tokens";
@@ -0,0 +1 @@
+export const format = (value) => String(value);
@@ -1,15 +1,36 @@
-import { BASIC_CONST, BASIC_LET, BASIC_VAR } from "@barrel-basic/constants";
-import { BASIC_CONST_SINGLE_EXPORT, BASIC_LET_SINGLE_EXPORT } from "@barrel-basic/single-export";
-import { BasicClass } from "@barrel-basic/classes";
-import { BasicEnum } from "@barrel-basic/enums";
-import { BasicInterface, type BasicType } from "@barrel-basic/types";
-import { ReExportedBasicConstToExport, ReExportedBasicType, RenamedBasicClass } from "@barrel-basic";
-import { basicFunction as basicFunctionWithAs, basicFunction } from "@barrel-basic/functions";
+import {
+  BASIC_CONST,
+  BASIC_LET,
+  BASIC_VAR,
+} from "@barrel-basic/constants";
+import {
+  BASIC_CONST_SINGLE_EXPORT,
+  BASIC_LET_SINGLE_EXPORT,
+} from "@barrel-basic/single-export";
+import {
+  BasicClass,
+  BasicClass as RenamedBasicClass,
+} from "@barrel-basic/classes";
+import {
+  BasicEnum,
+} from "@barrel-basic/enums";
+import {
+  BasicInterface,
+  type BasicType,
+} from "@barrel-basic/types";
+import {
+  ReExportedBasicConstToExport,
+  ReExportedBasicType,
+} from "@barrel-basic/re-exports";
+import {
+  basicFunction as basicFunctionWithAs,
+  basicFunction,
+} from "@barrel-basic/functions";
 
 import { CircularA } from '@barrel-circular/circular-a';
 import { CircularB } from '@barrel-circular/circular-b';
 
-import { Button } from "@barrel-nested/Buttons/button"
+import { Button, Button as PrimaryButton, Button as MainButton } from "@barrel-nested/Buttons/button"
 import { ButtonProps } from "@barrel-nested/Buttons/button.type"
 import { nestedConstant } from "@barrel-nested/nested-constant"
 import { nestedFunction } from "@barrel-nested/nested/nested-function"
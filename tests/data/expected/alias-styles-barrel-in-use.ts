@@ -0,0 +1,4 @@
+import { BASIC_CONST } from "~/barrel-basic/constants";
+import { nestedFunction } from "#app/nested/nested-function";
+import { CircularA } from "@barrel-circular/circular-a";
+import { useQuery } from "@tanstack/query";
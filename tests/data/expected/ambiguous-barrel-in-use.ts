@@ -0,0 +1,3 @@
+import { AMBIGUOUS_CONST } from "@barrel-basic";
+import { BASIC_LET_SINGLE_EXPORT } from "@barrel-basic/single-export";
+import { Button } from "./barrel-nested/Buttons/button";
@@ -0,0 +1,5 @@
+export default class DefaultClass {
+  sayHello() {
+    console.log("Hello from DefaultClass!");
+  }
+}
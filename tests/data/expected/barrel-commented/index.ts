@@ -0,0 +1,3 @@
+// export * from "./legacy";
+/* export * from "./legacy"; */
+export const legacyNotice = "export * from './legacy'";
@@ -0,0 +1 @@
+export const LEGACY = 42;
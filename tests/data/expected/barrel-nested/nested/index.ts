@@ -1,2 +1,3 @@
 export * from "./nested-function";
 export * from "../nested-constant";
+export { Button } from "../Buttons/button";
@@ -0,0 +1,4 @@
+import { BasicEnum } from "barrel-basic/enums";
+import { BasicClass } from "barrel-basic/classes";
+import { nestedConstant } from "barrel-nested/nested-constant";
+import { CircularA } from "barrel-circular";
@@ -0,0 +1,5 @@
+import basicDefault from "@barrel-basic/functions";
+import DefaultClass from "@barrel-basic/default-export";
+import { BASIC_CONST } from "@barrel-basic/constants";
+import IconButton, { IconButtonSize, type IconButtonProps } from "@barrel-nested/Buttons/icon-button";
+import type DefaultClassType from "./barrel-basic/default-export";
@@ -0,0 +1,7 @@
+export async function loadChart() {
+  const { BASIC_CONST, BasicConstants, basicFunction: format } = await import("@barrel-basic");
+  const { PrimaryButton } = await import('./barrel-nested');
+  return { BASIC_CONST, BasicConstants, format, PrimaryButton };
+}
+
+export const lazyNested = () => import("@barrel-nested");
@@ -0,0 +1,3 @@
+import { debounce, throttle as rateLimit } from "lodash-es";
+import * as dateFns from "date-fns";
+import { BASIC_CONST } from "@barrel-basic/constants";
@@ -0,0 +1,12 @@
+import { ButtonProps } from "@barrel-nested/Buttons/button.type";
+import { Button } from "@barrel-nested/Buttons/button";
+import { nestedFunction } from "@barrel-nested/nested/nested-function";
+import * as Basic from "./barrel-basic";
+import * as BasicConstants from "@barrel-basic/constants";
+import * as Constants from "@barrel-basic/constants";
+
+export const NamespaceButton = (props: ButtonProps) => (
+  <Button label={`${nestedFunction()} ${Basic.BASIC_CONST}`} />
+);
+export const constants = [BasicConstants.BASIC_CONST, Constants.BASIC_LET];
+export const basic = Basic;
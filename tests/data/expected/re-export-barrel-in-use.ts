@@ -0,0 +1,8 @@
+export { BASIC_CONST } from "@barrel-basic/constants";
+export * as BasicConstants from "@barrel-basic/constants";
+export { basicFunction as format } from "@barrel-basic/functions";
+export type { BasicType, BasicInterface } from "@barrel-basic/types";
+export { Button as MainButton } from './barrel-nested/Buttons/button'
+export { type ButtonProps } from './barrel-nested/Buttons/button.type'
+export * from "@barrel-nested";
+export const LOCAL_CONST = 1;
@@ -0,0 +1 @@
+exports.VERSION = "1.0.0";
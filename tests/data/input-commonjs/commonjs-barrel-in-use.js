@@ -0,0 +1,5 @@
+const { capitalize, numbers, parseDate, formatDate: format, chunk, VERSION } = require("./barrel-commonjs");
+const lib = require("./barrel-commonjs");
+let { lowercase } = require('./barrel-commonjs')
+
+module.exports = { capitalize, numbers, parseDate, format, chunk, VERSION, lib, lowercase };
@@ -0,0 +1,13 @@
+import {
+  // Buttons
+  Button, // primary action
+  IconButton,
+
+  Card /* deprecated */,
+  Modal
+} from "./components";
+import {Card as Tile} from "./components";
+
+export { Button as Action, Modal as Dialog } from "./components";
+
+export const App = () => [Button(), IconButton(), Card(), Modal(), Tile()];
@@ -0,0 +1 @@
+export const Modal = () => "modal";
@@ -0,0 +1,6 @@
+const {
+	Button,
+	Card: Tile, // legacy name
+} = require("./components");
+
+module.exports = () => [Button(), Tile()];
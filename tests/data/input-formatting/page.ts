@@ -0,0 +1,6 @@
+﻿import {
+  Button,
+  Card,
+} from "./components";
+
+export const Page = () => [Button(), Card()];
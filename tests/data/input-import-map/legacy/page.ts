@@ -0,0 +1,3 @@
+import { LegacyButton } from "@ui";
+
+export const Page = () => LegacyButton();
@@ -0,0 +1,3 @@
+import { Button, Input } from "@ui";
+
+export const App = () => [Button(), Input()];
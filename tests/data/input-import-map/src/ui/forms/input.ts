@@ -0,0 +1 @@
+export const Input = () => "input";
@@ -0,0 +1,3 @@
+import { Button, Card } from "@/components";
+
+export const Home = () => [Button(), Card()];
@@ -0,0 +1,3 @@
+import { trim } from "@/utils";
+
+export const main = () => trim(" main ");
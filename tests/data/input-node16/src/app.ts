@@ -0,0 +1,4 @@
+import { Button, Card, legacy } from "./components/index.js";
+import type { Theme } from "./components/index.js";
+
+export const App = (theme: Theme) => [Button(), Card(), legacy(), theme];
@@ -0,0 +1 @@
+export const Card = () => "card";
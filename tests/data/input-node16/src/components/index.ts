@@ -0,0 +1,4 @@
+export * from "./button/Button.js";
+export * from "./card/index.js";
+export * from "./legacy.mjs";
+export type { Theme } from "./types.js";
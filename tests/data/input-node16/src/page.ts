@@ -0,0 +1,4 @@
+import { Button, Card } from "./components/index.ts";
+import { legacy } from "./components";
+
+export const Page = () => [Button(), Card(), legacy()];
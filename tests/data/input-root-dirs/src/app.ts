@@ -0,0 +1,4 @@
+import { getUser, getOrder } from "./services";
+import { Api } from "./api";
+
+export const App = () => [getUser(), getOrder(), Api()];
@@ -0,0 +1,4 @@
+import { Button, Icon } from "#ui";
+import { Card } from "#widgets";
+
+export const App = () => [Button(), Icon(), Card()];
@@ -0,0 +1,8 @@
+import { Button, Card } from "@components";
+import { Header } from "@screens/home";
+import { useTheme } from "@hooks";
+import { formatDate } from "@utils";
+import { fetchUser } from "Services";
+import { addToCart } from "@store";
+
+export const App = () => [Button(), Card(), Header(), useTheme(), formatDate(new Date()), fetchUser(), addToCart(1)];
@@ -0,0 +1 @@
+export const fetchUser = () => fetch("/user");
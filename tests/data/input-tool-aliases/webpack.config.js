@@ -0,0 +1,11 @@
+const path = require("path");
+
+module.exports = {
+  entry: "./src/app.ts",
+  resolve: {
+    alias: {
+      Services$: path.resolve(__dirname, "src/services/index.js"),
+      "@store": path.join(__dirname, "src", "store"),
+    },
+  },
+};
@@ -0,0 +1,4 @@
+import { Button, Card, theme, tokens } from "@acme/ui";
+import { format } from "@acme/utils";
+
+export const App = () => [Button(), Card(), theme, tokens, format(1)];
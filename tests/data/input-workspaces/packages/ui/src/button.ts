@@ -0,0 +1 @@
+export const Button = () => "button";
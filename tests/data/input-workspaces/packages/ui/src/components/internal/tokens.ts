@@ -0,0 +1 @@
+export const tokens = { spacing: 4 };
@@ -0,0 +1 @@
+export const theme = { dark: false };
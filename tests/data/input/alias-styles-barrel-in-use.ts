@@ -0,0 +1,4 @@
+import { BASIC_CONST } from "~/barrel-basic";
+import { nestedFunction } from "#app/nested";
+import { CircularA } from "@barrel-circular";
+import { useQuery } from "@tanstack/query";
@@ -0,0 +1,2 @@
+import { AMBIGUOUS_CONST, BASIC_LET_SINGLE_EXPORT } from "@barrel-basic";
+import { Button } from "./barrel-nested";
@@ -0,0 +1 @@
+export const AMBIGUOUS_CONST = "a";
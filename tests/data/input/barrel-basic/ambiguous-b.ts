@@ -0,0 +1,2 @@
+export const AMBIGUOUS_CONST = "b";
+export const BASIC_LET_SINGLE_EXPORT = "b";
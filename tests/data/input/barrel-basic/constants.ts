@@ -1,3 +1,4 @@
 export const BASIC_CONST = 42;
 export let BASIC_LET = 42;
 export var BASIC_VAR = 42;
+export const UNICODE_ÉLAN = 42;
@@ -0,0 +1,24 @@
+export async function asyncFunction() {}
+export function* generatorFunction() {}
+export abstract class AbstractClass {}
+export declare const declaredConst: number;
+export const enum ConstEnum {
+  A,
+  B,
+}
+export namespace BasicNamespace {
+  export const value = 42;
+}
+
+const source = { first: 1, second: { third: 3 }, items: [4, 5, 6] };
+export const {
+  first,
+  second: { third: renamedThird },
+  items: [fourth, ...others],
+} = source;
+export const listed = 1,
+  otherListed: Map<string, number> = new Map(),
+  lastListed = [1, 2].map((value) => value * 2)
+
+const a = 1, b = 2;
+export { a, b as renamedB };
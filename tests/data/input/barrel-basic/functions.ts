@@ -1,3 +1,5 @@
 export function basicFunction() {
   console.log("basicFunction called");
 }
+
+export default basicFunction;
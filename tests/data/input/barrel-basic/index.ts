@@ -8,3 +8,11 @@ export * from "./re-exports";
 export * from "./types";
 export { BASIC_LET_SINGLE_EXPORT } from "./single-export";
 export { BASIC_CONST_SINGLE_EXPORT };
+export { default } from "./functions";
+export { default as DefaultClass } from "./default-export";
+export * as BasicConstants from "./constants";
+export * from "./declarations";
+export * from "./ambiguous-a";
+export * from "./ambiguous-b";
+export { debounce, throttle as rateLimit } from "lodash-es";
+export * as dateFns from "date-fns";
@@ -0,0 +1,6 @@
+export type IconButtonProps = { icon: string };
+export const IconButtonSize = 24;
+
+export default function IconButton({ icon }: IconButtonProps) {
+  return <button>{icon}</button>;
+}
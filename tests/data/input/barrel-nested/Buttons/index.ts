@@ -1,2 +1,4 @@
 export * from './button'
-export * from './button.type'
\ No newline at end of file
+export * from './button.type'
+export * from './icon-button'
+export { default as IconButton } from './icon-button'
@@ -1,2 +1,3 @@
 export * from "./nested";
-export * from "./Buttons"
\ No newline at end of file
+export * from "./Buttons"
+export { Button as PrimaryButton } from "./Buttons";
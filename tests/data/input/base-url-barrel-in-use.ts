@@ -0,0 +1,3 @@
+import { BasicEnum, BasicClass } from "barrel-basic";
+import { nestedConstant } from "barrel-nested";
+import { CircularA } from "barrel-circular";
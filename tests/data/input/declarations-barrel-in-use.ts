@@ -0,0 +1,17 @@
+import {
+  asyncFunction,
+  generatorFunction,
+  AbstractClass,
+  declaredConst,
+  ConstEnum,
+  BasicNamespace,
+  first,
+  renamedThird,
+  fourth,
+  others,
+  listed,
+  otherListed,
+  lastListed,
+  a,
+  renamedB,
+} from "@barrel-basic";
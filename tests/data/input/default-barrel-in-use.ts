@@ -0,0 +1,3 @@
+import basicDefault, { DefaultClass, BASIC_CONST } from "@barrel-basic";
+import { IconButton, IconButtonSize, type IconButtonProps } from "@barrel-nested";
+import type { DefaultClass as DefaultClassType } from "./barrel-basic";
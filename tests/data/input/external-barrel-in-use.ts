@@ -0,0 +1 @@
+import { debounce, rateLimit, dateFns, BASIC_CONST } from "@barrel-basic";
@@ -0,0 +1,9 @@
+import * as Nested from "@barrel-nested";
+import * as Basic from "./barrel-basic";
+import { BasicConstants, BasicConstants as Constants } from "@barrel-basic";
+
+export const NamespaceButton = (props: Nested.ButtonProps) => (
+  <Nested.Button label={`${Nested.nestedFunction()} ${Basic.BASIC_CONST}`} />
+);
+export const constants = [BasicConstants.BASIC_CONST, Constants.BASIC_LET];
+export const basic = Basic;
@@ -0,0 +1,5 @@
+export { BASIC_CONST, basicFunction as format, BasicConstants } from "@barrel-basic";
+export type { BasicType, BasicInterface } from "@barrel-basic";
+export { PrimaryButton as MainButton, type ButtonProps } from './barrel-nested'
+export * from "@barrel-nested";
+export const LOCAL_CONST = 1;
@@ -0,0 +1,14 @@
+// import { BasicClass } from "@barrel-basic";
+/* import { BasicEnum } from "@barrel-basic"; */
+const singleQuoted = 'import { BasicClass } from "@barrel-basic";';
+const template = `
+import { BasicEnum } from "@barrel-basic";
+${singleQuoted}
+`;
+
+import type
+  { BasicType }
+  from
+  "@barrel-basic";
+
+import { UNICODE_ÉLAN } from "./barrel-basic";